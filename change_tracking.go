@@ -0,0 +1,316 @@
+package neo2go
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// Implemented by NeoNode and NeoRelationship, which track the local modifications
+// of their properties (and labels), until they are sent with GraphDatabaseService.Flush.
+type Flushable interface {
+	// Returns true, if there are modifications, which have not been flushed yet.
+	IsDirty() bool
+	// Forgets the modifications, restoring the original properties.
+	DiscardChanges()
+	queueChanges(batch *NeoBatch) error
+	clearChanges()
+}
+
+var _ Flushable = (*NeoNode)(nil)
+var _ Flushable = (*NeoRelationship)(nil)
+
+// The state of the properties before the first local modification.
+type propertyChanges struct {
+	// Nil, if the properties have not been modified.
+	original      map[string]json.RawMessage
+	addedLabels   []string
+	removedLabels []string
+}
+
+// Returns the keys of the properties to set, and the keys of the properties to delete.
+func (p *propertyChanges) diff(current map[string]json.RawMessage) ([]string, []string) {
+	if p.original == nil {
+		return nil, nil
+	}
+	var set, deleted []string
+	for key, value := range current {
+		if original, ok := p.original[key]; !ok || !jsonEqual(original, value) {
+			set = append(set, key)
+		}
+	}
+	for key := range p.original {
+		if _, ok := current[key]; !ok {
+			deleted = append(deleted, key)
+		}
+	}
+	sort.Strings(set)
+	sort.Strings(deleted)
+	return set, deleted
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var compactA, compactB bytes.Buffer
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+}
+
+func toggleLabel(label string, add *[]string, remove *[]string) {
+	for i, l := range *remove {
+		if l == label {
+			*remove = append((*remove)[:i], (*remove)[i+1:]...)
+			return
+		}
+	}
+	for _, l := range *add {
+		if l == label {
+			return
+		}
+	}
+	*add = append(*add, label)
+}
+
+func (n *NeoNode) properties() (map[string]json.RawMessage, error) {
+	properties := make(map[string]json.RawMessage)
+	if len(n.Data) > 0 && !isJsonNull(n.Data) {
+		if err := json.Unmarshal(n.Data, &properties); err != nil {
+			return nil, err
+		}
+	}
+	return properties, nil
+}
+
+func (n *NeoNode) modifyProperties(modify func(map[string]json.RawMessage) error) error {
+	properties, err := n.properties()
+	if err != nil {
+		return err
+	}
+	if n.changes.original == nil {
+		n.changes.original = make(map[string]json.RawMessage, len(properties))
+		for key, value := range properties {
+			n.changes.original[key] = value
+		}
+	}
+	if err := modify(properties); err != nil {
+		return err
+	}
+	data, err := json.Marshal(properties)
+	if err != nil {
+		return err
+	}
+	n.Data = data
+	return nil
+}
+
+// Sets the property locally; the change is sent to the server by GraphDatabaseService.Flush.
+func (n *NeoNode) SetProperty(key string, value interface{}) error {
+	return n.SetProperties(map[string]interface{}{key: value})
+}
+
+// Sets the properties locally, keeping the other ones.
+func (n *NeoNode) SetProperties(properties map[string]interface{}) error {
+	return n.modifyProperties(func(current map[string]json.RawMessage) error {
+		for key, value := range properties {
+			encoded, err := encodePropertyValue(value)
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(encoded)
+			if err != nil {
+				return err
+			}
+			current[key] = data
+		}
+		return nil
+	})
+}
+
+// Deletes the property locally.
+func (n *NeoNode) DeleteProperty(key string) error {
+	return n.modifyProperties(func(current map[string]json.RawMessage) error {
+		delete(current, key)
+		return nil
+	})
+}
+
+// Adds the label locally.
+func (n *NeoNode) AddLabel(label string) {
+	toggleLabel(label, &n.changes.addedLabels, &n.changes.removedLabels)
+}
+
+// Removes the label locally.
+func (n *NeoNode) RemoveLabel(label string) {
+	toggleLabel(label, &n.changes.removedLabels, &n.changes.addedLabels)
+}
+
+func (n *NeoNode) IsDirty() bool {
+	if len(n.changes.addedLabels) > 0 || len(n.changes.removedLabels) > 0 {
+		return true
+	}
+	properties, err := n.properties()
+	if err != nil {
+		return true
+	}
+	set, deleted := n.changes.diff(properties)
+	return len(set) > 0 || len(deleted) > 0
+}
+
+func (n *NeoNode) DiscardChanges() {
+	if n.changes.original != nil {
+		n.Data, _ = json.Marshal(n.changes.original)
+	}
+	n.clearChanges()
+}
+
+func (n *NeoNode) clearChanges() {
+	n.changes = propertyChanges{}
+}
+
+func (n *NeoNode) queueChanges(batch *NeoBatch) error {
+	properties, err := n.properties()
+	if err != nil {
+		return err
+	}
+	set, deleted := n.changes.diff(properties)
+	for _, key := range set {
+		if resp := batch.SetPropertyForNode(n, key, properties[key]); resp.Err != nil {
+			return resp.Err
+		}
+	}
+	for _, key := range deleted {
+		if resp := batch.DeletePropertyWithKeyForNode(n, key); resp.Err != nil {
+			return resp.Err
+		}
+	}
+	if len(n.changes.addedLabels) > 0 {
+		batch.AddLabels(n, n.changes.addedLabels)
+	}
+	for _, label := range n.changes.removedLabels {
+		batch.RemoveLabel(n, label)
+	}
+	return nil
+}
+
+func (n *NeoRelationship) properties() (map[string]json.RawMessage, error) {
+	properties := make(map[string]json.RawMessage, len(n.Data))
+	for key, value := range n.Data {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		properties[key] = data
+	}
+	return properties, nil
+}
+
+func (n *NeoRelationship) snapshotProperties() error {
+	if n.changes.original != nil {
+		return nil
+	}
+	properties, err := n.properties()
+	if err != nil {
+		return err
+	}
+	n.changes.original = properties
+	return nil
+}
+
+// Sets the property locally; the change is sent to the server by GraphDatabaseService.Flush.
+func (n *NeoRelationship) SetProperty(key string, value interface{}) error {
+	return n.SetProperties(map[string]interface{}{key: value})
+}
+
+// Sets the properties locally, keeping the other ones.
+func (n *NeoRelationship) SetProperties(properties map[string]interface{}) error {
+	if err := n.snapshotProperties(); err != nil {
+		return err
+	}
+	if n.Data == nil {
+		n.Data = make(map[string]interface{})
+	}
+	for key, value := range properties {
+		encoded, err := encodePropertyValue(value)
+		if err != nil {
+			return err
+		}
+		n.Data[key] = encoded
+	}
+	return nil
+}
+
+// Deletes the property locally.
+func (n *NeoRelationship) DeleteProperty(key string) error {
+	if err := n.snapshotProperties(); err != nil {
+		return err
+	}
+	delete(n.Data, key)
+	return nil
+}
+
+func (n *NeoRelationship) IsDirty() bool {
+	properties, err := n.properties()
+	if err != nil {
+		return true
+	}
+	set, deleted := n.changes.diff(properties)
+	return len(set) > 0 || len(deleted) > 0
+}
+
+func (n *NeoRelationship) DiscardChanges() {
+	if n.changes.original != nil {
+		n.Data = make(map[string]interface{}, len(n.changes.original))
+		for key, value := range n.changes.original {
+			var decoded interface{}
+			json.Unmarshal(value, &decoded)
+			n.Data[key] = decoded
+		}
+	}
+	n.clearChanges()
+}
+
+func (n *NeoRelationship) clearChanges() {
+	n.changes = propertyChanges{}
+}
+
+func (n *NeoRelationship) queueChanges(batch *NeoBatch) error {
+	properties, err := n.properties()
+	if err != nil {
+		return err
+	}
+	set, deleted := n.changes.diff(properties)
+	for _, key := range set {
+		if resp := batch.SetPropertyForRelationship(n, key, properties[key]); resp.Err != nil {
+			return resp.Err
+		}
+	}
+	for _, key := range deleted {
+		if resp := batch.DeletePropertyWithKeyForRelationship(n, key); resp.Err != nil {
+			return resp.Err
+		}
+	}
+	return nil
+}
+
+// Sends the local modifications of the nodes and relationships in one batch: a property is set
+// only if its value has changed, and deleted only if it existed. On success, the entities are clean.
+func (g *GraphDatabaseService) Flush(entities ...Flushable) *NeoResponse {
+	batch := g.Batch()
+	for _, entity := range entities {
+		if err := entity.queueChanges(batch); err != nil {
+			return NewLocalErrorResponse(200, err)
+		}
+	}
+	if batch.currentBatchId == 0 {
+		return &NeoResponse{ExpectedCode: 200, StatusCode: 200}
+	}
+
+	resp := batch.Commit()
+	if resp.Ok() {
+		for _, entity := range entities {
+			entity.clearChanges()
+		}
+	}
+	return resp
+}
@@ -0,0 +1,75 @@
+package neo2go
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFlushChanges(t *testing.T) {
+	server, requests := newFakeBatchServer(t, func(method, to string) (int, string) {
+		return 204, ""
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	node, err := service.nodeFromId(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.Data = json.RawMessage(`{"name": "Alice", "age": 30}`)
+	rel := new(NeoRelationship)
+	relJson := `{"self":"%[1]s/db/data/relationship/3","properties":"%[1]s/db/data/relationship/3/properties",
+		"property":"%[1]s/db/data/relationship/3/properties/{key}","data":{"since":2010}}`
+	if err := json.Unmarshal([]byte(strings.ReplaceAll(relJson, "%[1]s", server.URL)), rel); err != nil {
+		t.Fatal(err)
+	}
+
+	node.SetProperty("age", 30)
+	node.DeleteProperty("missing")
+	node.AddLabel("Person")
+	node.RemoveLabel("Person")
+	if node.IsDirty() {
+		t.Fatalf("Expected the node to be clean, but it has changes: %+v", node.changes)
+	}
+
+	node.SetProperties(map[string]interface{}{"name": "Bob", "email": "bob@example.com"})
+	node.DeleteProperty("age")
+	node.AddLabel("Employee")
+	node.AddLabel("Manager")
+	node.RemoveLabel("Intern")
+	rel.SetProperty("since", 2011)
+	if !node.IsDirty() || !rel.IsDirty() {
+		t.Fatalf("Expected the entities to be dirty.")
+	}
+	var parsed struct{ Name string }
+	if node.ParseData(&parsed); parsed.Name != "Bob" {
+		t.Errorf("Expected the local changes to be visible, but got %v", parsed.Name)
+	}
+
+	checkResponseSucceeded(t, service.Flush(node, rel), 200)
+	if node.IsDirty() || rel.IsDirty() {
+		t.Errorf("Expected the entities to be clean after flushing.")
+	}
+	expected := []string{
+		`PUT /node/5/properties/email "bob@example.com"`,
+		`PUT /node/5/properties/name "Bob"`,
+		"DELETE /node/5/properties/age",
+		`POST /node/5/labels ["Employee","Manager"]`,
+		"DELETE /node/5/labels/Intern",
+		"PUT /relationship/3/properties/since 2011",
+	}
+	if actual := strings.Join(*requests, "\n"); actual != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected requests:\n%v", actual)
+	}
+
+	*requests = nil
+	node.SetProperty("name", "Carol")
+	node.DiscardChanges()
+	checkResponseSucceeded(t, service.Flush(node), 200)
+	if len(*requests) != 0 {
+		t.Errorf("Expected no requests, but got: %v", *requests)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/armatys/neo2go"
+)
+
+var databaseUrl *string = flag.String("url", "http://localhost:7474", "Neo4j server url")
+var username *string = flag.String("username", "", "Neo4j username")
+var password *string = flag.String("password", "", "Neo4j password")
+var migrationsDir *string = flag.String("dir", "migrations", "Directory with the migration files (<version>_<name>.cypher)")
+var label *string = flag.String("label", "SchemaMigration", "Label of the nodes recording the applied migrations")
+var dryRun *bool = flag.Bool("dry-run", false, "Print the pending migrations, without applying them")
+var verifyOnly *bool = flag.Bool("verify", false, "Only verify the checksums of the applied migrations")
+
+func main() {
+	flag.Parse()
+
+	migrations, err := neo2go.ReadMigrations(*migrationsDir)
+	if err != nil {
+		log.Fatalf("Could not read the migrations: %v\n", err)
+	}
+
+	service := neo2go.NewGraphDatabaseService()
+	if *username != "" {
+		service.SetBasicAuth(*username, *password)
+	}
+	if resp := service.Connect(*databaseUrl); !resp.Ok() {
+		log.Fatalf("Could not connect to %v: %v\n", *databaseUrl, resp.Err)
+	}
+
+	migrator := neo2go.NewMigrator(service)
+	migrator.Label = *label
+	migrator.DryRun = *dryRun
+
+	if *verifyOnly {
+		if resp := migrator.Verify(migrations); !resp.Ok() {
+			log.Fatalf("Verification failed: %v\n", resp.Err)
+		}
+		fmt.Println("The applied migrations are valid.")
+		return
+	}
+
+	applied, resp := migrator.Migrate(migrations)
+	for _, migration := range applied {
+		if *dryRun {
+			fmt.Printf("Pending %d %v\n", migration.Version, migration.Name)
+			for _, statement := range migration.Statements {
+				fmt.Printf("\t%v\n", statement)
+			}
+		} else {
+			fmt.Printf("Applied %d %v\n", migration.Version, migration.Name)
+		}
+	}
+	if !resp.Ok() {
+		log.Printf("Migration failed: %v\n", resp.Err)
+		os.Exit(1)
+	}
+	if len(applied) == 0 {
+		fmt.Println("No pending migrations.")
+	}
+}
@@ -0,0 +1,106 @@
+package neo2go
+
+import (
+	"fmt"
+	"log"
+)
+
+// Statistics of a transactional Cypher statement, returned when CypherTransactionRequest.IncludeStats is set.
+type CypherStats struct {
+	ContainsUpdates      bool `json:"contains_updates"`
+	NodesCreated         int  `json:"nodes_created"`
+	NodesDeleted         int  `json:"nodes_deleted"`
+	PropertiesSet        int  `json:"properties_set"`
+	RelationshipsCreated int  `json:"relationships_created"`
+	// The server uses the singular form for this key.
+	RelationshipsDeleted int `json:"relationship_deleted"`
+	LabelsAdded          int `json:"labels_added"`
+	LabelsRemoved        int `json:"labels_removed"`
+	IndexesAdded         int `json:"indexes_added"`
+	IndexesRemoved       int `json:"indexes_removed"`
+	ConstraintsAdded     int `json:"constraints_added"`
+	ConstraintsRemoved   int `json:"constraints_removed"`
+}
+
+type CypherNotificationPosition struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// A warning or a hint about a statement, e.g. Neo_ClientNotification_Statement_CartesianProduct.
+type CypherNotification struct {
+	Code        string                      `json:"code"`
+	Severity    string                      `json:"severity"`
+	Title       string                      `json:"title"`
+	Description string                      `json:"description"`
+	Position    *CypherNotificationPosition `json:"position"`
+}
+
+func (c *CypherNotification) String() string {
+	if c.Position != nil {
+		return fmt.Sprintf("%v %v (line %d, column %d): %v", c.Severity, c.Code, c.Position.Line, c.Position.Column, c.Title)
+	}
+	return fmt.Sprintf("%v %v: %v", c.Severity, c.Code, c.Title)
+}
+
+// Called with the notifications returned by a transactional Cypher request.
+// If the handler returns an error, the request fails with that error (as a local error).
+// An open transaction is rolled back at that point; note that for a commit,
+// the transaction has already been committed.
+type CypherNotificationHandler func(notifications []CypherNotification) error
+
+// Calls the handler with the notifications of every transactional Cypher request made by the service.
+func WithNotificationHandler(handler CypherNotificationHandler) ServiceOption {
+	return func(c *serviceConfig) {
+		c.notificationHandler = handler
+	}
+}
+
+// Returns a handler, which fails a request if any of its notifications has one of the given codes
+// (all notifications, if no code is given). Useful in tests, e.g. to reject Cartesian products.
+func FailOnNotifications(codes ...string) CypherNotificationHandler {
+	return func(notifications []CypherNotification) error {
+		for i := range notifications {
+			if len(codes) == 0 {
+				return fmt.Errorf("Cypher notification: %v", notifications[i].String())
+			}
+			for _, code := range codes {
+				if notifications[i].Code == code {
+					return fmt.Errorf("Cypher notification: %v", notifications[i].String())
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// Returns a handler, which logs all notifications. If logger is nil, the standard logger is used.
+func LogNotifications(logger *log.Logger) CypherNotificationHandler {
+	return func(notifications []CypherNotification) error {
+		for i := range notifications {
+			if logger != nil {
+				logger.Printf("Cypher notification: %v", notifications[i].String())
+			} else {
+				log.Printf("Cypher notification: %v", notifications[i].String())
+			}
+		}
+		return nil
+	}
+}
+
+func (g *GraphDatabaseService) executeCypherFromRequestData(result *CypherTransaction, reqData *neoRequestData) *NeoResponse {
+	resp := g.executeFromRequestData(reqData)
+	if resp.Ok() && g.notificationHandler != nil && len(result.Notifications) > 0 {
+		if err := g.notificationHandler(result.Notifications); err != nil {
+			if result.Self != nil && result.Commit != nil {
+				// The transaction is still open.
+				if rollback := g.RollbackCypher(result); !rollback.Ok() {
+					err = fmt.Errorf("%w (the transaction could not be rolled back: %v)", err, rollback.Err)
+				}
+			}
+			return NewLocalErrorResponse(reqData.expectedStatus, err)
+		}
+	}
+	return resp
+}
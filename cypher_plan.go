@@ -0,0 +1,198 @@
+package neo2go
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The execution plan of a statement prefixed with EXPLAIN or PROFILE.
+// Only a profiled plan contains the actual rows and database hits.
+type CypherPlan struct {
+	Root *CypherPlanOperator
+}
+
+// The transactional endpoint wraps the root operator in {"root": ...},
+// while the legacy Cypher endpoint returns the operator directly.
+func (c *CypherPlan) UnmarshalJSON(data []byte) error {
+	var wrapper struct {
+		Root json.RawMessage `json:"root"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return err
+	}
+	if wrapper.Root != nil {
+		data = wrapper.Root
+	}
+	c.Root = new(CypherPlanOperator)
+	return json.Unmarshal(data, c.Root)
+}
+
+func (c *CypherPlan) String() string {
+	if c.Root == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	c.Root.write(&buf, 0)
+	return buf.String()
+}
+
+type CypherPlanOperator struct {
+	OperatorType  string
+	Identifiers   []string
+	EstimatedRows float64
+	DbHits        int64
+	Rows          int64
+	// The remaining arguments of the operator (e.g. LegacyExpression, KeyNames).
+	Arguments map[string]interface{}
+	Children  []*CypherPlanOperator
+}
+
+// Handles both the transactional format, in which the arguments are inlined with capitalized
+// names ("EstimatedRows", "DbHits"), and the legacy format ("name", "args", "_rows", "_db_hits").
+func (c *CypherPlanOperator) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	c.Arguments = make(map[string]interface{})
+	if args, ok := fields["args"]; ok {
+		if err := json.Unmarshal(args, &c.Arguments); err != nil {
+			return err
+		}
+		delete(fields, "args")
+	}
+	for key, value := range fields {
+		var target interface{}
+		switch key {
+		case "operatorType", "name":
+			target = &c.OperatorType
+		case "identifiers":
+			target = &c.Identifiers
+		case "children":
+			target = &c.Children
+		default:
+			var arg interface{}
+			if err := json.Unmarshal(value, &arg); err != nil {
+				return err
+			}
+			c.Arguments[key] = arg
+			continue
+		}
+		if err := json.Unmarshal(value, target); err != nil {
+			return fmt.Errorf("Could not parse the '%v' of a plan operator: %v", key, err)
+		}
+	}
+
+	for key, arg := range c.Arguments {
+		number, ok := arg.(float64)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.ReplaceAll(key, "_", "")) {
+		case "estimatedrows":
+			c.EstimatedRows = number
+		case "dbhits":
+			c.DbHits = int64(number)
+		case "rows":
+			c.Rows = int64(number)
+		default:
+			continue
+		}
+		delete(c.Arguments, key)
+	}
+	return nil
+}
+
+func (c *CypherPlanOperator) write(buf *bytes.Buffer, depth int) {
+	fmt.Fprintf(buf, "%s+ %s", strings.Repeat("  ", depth), c.OperatorType)
+	if len(c.Identifiers) > 0 {
+		fmt.Fprintf(buf, " [%s]", strings.Join(c.Identifiers, ", "))
+	}
+	fmt.Fprintf(buf, " (estimated rows: %g, rows: %d, db hits: %d)", c.EstimatedRows, c.Rows, c.DbHits)
+
+	keys := make([]string, 0, len(c.Arguments))
+	for key := range c.Arguments {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(buf, " %s=%v", key, c.Arguments[key])
+	}
+	buf.WriteString("\n")
+
+	for _, child := range c.Children {
+		child.write(buf, depth+1)
+	}
+}
+
+// Returns a copy of the request, with the statement prefixed with EXPLAIN.
+// The statement is not executed, but the result contains its plan.
+func ExplainStatement(request *CypherTransactionRequest) *CypherTransactionRequest {
+	return prefixedStatement("EXPLAIN", request)
+}
+
+// Returns a copy of the request, with the statement prefixed with PROFILE.
+// The statement is executed, and the result contains its plan with the actual rows and database hits.
+func ProfileStatement(request *CypherTransactionRequest) *CypherTransactionRequest {
+	return prefixedStatement("PROFILE", request)
+}
+
+func prefixedStatement(prefix string, request *CypherTransactionRequest) *CypherTransactionRequest {
+	prefixed := *request
+	prefixed.Cql = prefix + " " + request.Cql
+	return &prefixed
+}
+
+// Returns the plan of the statement, without executing it.
+func (g *GraphDatabaseService) ExplainCypher(cql string, params map[string]interface{}) (*CypherPlan, *NeoResponse) {
+	result, resp := g.CypherAutoCommit(ExplainStatement(&CypherTransactionRequest{Cql: cql, Params: params}))
+	if !resp.Ok() {
+		return nil, resp
+	}
+	if len(result.Results) == 0 || result.Results[0].Plan == nil {
+		return nil, NewLocalErrorResponse(resp.ExpectedCode, fmt.Errorf("The server did not return a plan."))
+	}
+	return result.Results[0].Plan, resp
+}
+
+// Executes (and commits) the statement, returning its result together with the profiled plan (CypherResult.Plan).
+func (g *GraphDatabaseService) ProfileCypher(cql string, params map[string]interface{}) (*CypherResult, *NeoResponse) {
+	result, resp := g.CypherAutoCommit(ProfileStatement(&CypherTransactionRequest{Cql: cql, Params: params}))
+	if !resp.Ok() {
+		return nil, resp
+	}
+	if len(result.Results) == 0 || result.Results[0].Plan == nil {
+		return nil, NewLocalErrorResponse(resp.ExpectedCode, fmt.Errorf("The server did not return a plan."))
+	}
+	return &result.Results[0], resp
+}
+
+// Returns the plan of the statement, using the legacy Cypher endpoint, without executing it.
+func (g *GraphDatabaseService) ExplainLegacyCypher(cql string, params map[string]interface{}) (*CypherPlan, *NeoResponse) {
+	result, resp := g.Cypher("EXPLAIN "+cql, params)
+	if !resp.Ok() {
+		return nil, resp
+	}
+	if result.Plan == nil {
+		return nil, NewLocalErrorResponse(resp.ExpectedCode, fmt.Errorf("The server did not return a plan."))
+	}
+	return result.Plan, resp
+}
+
+// Executes the statement using the legacy Cypher endpoint, returning its result together
+// with the profiled plan (CypherResponse.Plan).
+func (g *GraphDatabaseService) ProfileLegacyCypher(cql string, params map[string]interface{}) (*CypherResponse, *NeoResponse) {
+	result, reqData := g.builder.ProfiledCypher(cql, params)
+	resp := g.executeFromRequestData(reqData)
+	if !resp.Ok() {
+		return nil, resp
+	}
+	if result.Plan == nil {
+		return nil, NewLocalErrorResponse(resp.ExpectedCode, fmt.Errorf("The server did not return a plan."))
+	}
+	return result, resp
+}
@@ -0,0 +1,237 @@
+package neo2go
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var neoNodeType = reflect.TypeOf(NeoNode{})
+var neoRelationshipType = reflect.TypeOf(NeoRelationship{})
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Iterates over the rows of a Cypher result, and scans them into Go values.
+//
+// A row can be scanned either positionally (one destination per column), or into a single struct.
+// The columns are mapped to the struct fields using `neo:"column"` tags; fields without a tag
+// match the column with the same name (case-insensitive). Fields tagged with `neo:"-"` are skipped.
+// If none of the fields match, and the row has a single column, the column itself is scanned
+// into the struct.
+//
+// Columns containing nodes or relationships (in the REST format) can be scanned into
+// NeoNode and NeoRelationship values; when scanned into any other struct, the properties (`data`)
+// of the node or relationship are decoded into it.
+type CypherRows struct {
+	columns []string
+	rows    [][]json.RawMessage
+	index   int
+}
+
+func newCypherRows(columns []string, rows [][]json.RawMessage) *CypherRows {
+	return &CypherRows{columns: columns, rows: rows, index: -1}
+}
+
+func (c *CypherResponse) Rows() *CypherRows {
+	return newCypherRows(c.Columns, c.Data)
+}
+
+// Scans all rows into the slice pointed to by dest (e.g. *[]Person or *[]*Person).
+func (c *CypherResponse) ScanAll(dest interface{}) error {
+	return c.Rows().ScanAll(dest)
+}
+
+func (c *CypherResult) Rows() *CypherRows {
+	rows := make([][]json.RawMessage, len(c.Data))
+	for i, row := range c.Data {
+		rows[i] = row.Values()
+	}
+	return newCypherRows(c.Columns, rows)
+}
+
+// Scans all rows into the slice pointed to by dest (e.g. *[]Person or *[]*Person).
+func (c *CypherResult) ScanAll(dest interface{}) error {
+	return c.Rows().ScanAll(dest)
+}
+
+// Advances to the next row. Returns false, when there are no more rows.
+func (c *CypherRows) Next() bool {
+	if c.index+1 >= len(c.rows) {
+		c.index = len(c.rows)
+		return false
+	}
+	c.index += 1
+	return true
+}
+
+func (c *CypherRows) Columns() []string {
+	return c.columns
+}
+
+// Returns the raw values of the current row.
+func (c *CypherRows) Row() []json.RawMessage {
+	if c.index < 0 || c.index >= len(c.rows) {
+		return nil
+	}
+	return c.rows[c.index]
+}
+
+// Scans the current row into dest.
+func (c *CypherRows) Scan(dest ...interface{}) error {
+	row := c.Row()
+	if row == nil {
+		return fmt.Errorf("Scan called without a successful call to Next.")
+	}
+	return ScanCypherRow(c.columns, row, dest...)
+}
+
+// Scans the remaining rows into the slice pointed to by dest.
+func (c *CypherRows) ScanAll(dest interface{}) error {
+	slicePtr := reflect.ValueOf(dest)
+	if slicePtr.Kind() != reflect.Ptr || slicePtr.IsNil() || slicePtr.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("The destination must be a pointer to a slice, but got %T.", dest)
+	}
+	slice := slicePtr.Elem()
+	elemType := slice.Type().Elem()
+
+	for c.Next() {
+		var elem reflect.Value
+		if elemType.Kind() == reflect.Ptr {
+			elem = reflect.New(elemType.Elem())
+		} else {
+			elem = reflect.New(elemType)
+		}
+		if err := c.Scan(elem.Interface()); err != nil {
+			return err
+		}
+		if elemType.Kind() == reflect.Ptr {
+			slice = reflect.Append(slice, elem)
+		} else {
+			slice = reflect.Append(slice, elem.Elem())
+		}
+	}
+	slicePtr.Elem().Set(slice)
+	return nil
+}
+
+// Scans a single row with the given columns into dest (see CypherRows).
+func ScanCypherRow(columns []string, row []json.RawMessage, dest ...interface{}) error {
+	if len(dest) == 1 {
+		target := reflect.ValueOf(dest[0])
+		if target.Kind() != reflect.Ptr || target.IsNil() {
+			return fmt.Errorf("The destination must be a non-nil pointer, but got %T.", dest[0])
+		}
+		if elem := target.Elem(); elem.Kind() == reflect.Struct && !isCypherLeafType(elem.Type()) {
+			if len(row) != len(columns) {
+				return fmt.Errorf("Expected %d columns, but the row has %d.", len(columns), len(row))
+			}
+			if fields := cypherColumnFields(elem.Type(), columns); len(fields) > 0 {
+				for column, fieldIndex := range fields {
+					if err := decodeCypherValue(row[column], elem.Field(fieldIndex)); err != nil {
+						return fmt.Errorf("Could not scan column '%v': %v", columns[column], err)
+					}
+				}
+				return nil
+			}
+		}
+	}
+
+	if len(dest) != len(row) {
+		return fmt.Errorf("Expected %d destination values, but the row has %d columns.", len(dest), len(row))
+	}
+	for i, d := range dest {
+		target := reflect.ValueOf(d)
+		if target.Kind() != reflect.Ptr || target.IsNil() {
+			return fmt.Errorf("The destination must be a non-nil pointer, but got %T.", d)
+		}
+		if err := decodeCypherValue(row[i], target.Elem()); err != nil {
+			return fmt.Errorf("Could not scan column #%d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Maps column indices to the indices of the struct fields.
+func cypherColumnFields(structType reflect.Type, columns []string) map[int]int {
+	fields := make(map[int]int)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" || field.Anonymous {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("neo"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		for column, name := range columns {
+			if (tag != "" && tag == name) || (tag == "" && strings.EqualFold(field.Name, name)) {
+				fields[column] = i
+			}
+		}
+	}
+	return fields
+}
+
+// Types which are decoded as a whole, instead of being treated as containers for node properties.
+func isCypherLeafType(t reflect.Type) bool {
+	return t == neoNodeType || t == neoRelationshipType || reflect.PtrTo(t).Implements(jsonUnmarshalerType) ||
+		hasPropertyCodec(t)
+}
+
+func isJsonNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// If the value is a node or a relationship in the REST format, returns its properties.
+func restEntityData(raw json.RawMessage) (json.RawMessage, bool) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, false
+	}
+	var entity struct {
+		Self string          `json:"self"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(trimmed, &entity); err != nil || entity.Self == "" || entity.Data == nil {
+		return nil, false
+	}
+	return entity.Data, true
+}
+
+func decodeCypherValue(raw json.RawMessage, v reflect.Value) error {
+	switch {
+	case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.Struct && !isCypherLeafType(v.Type().Elem()):
+		if isJsonNull(raw) {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeCypherValue(raw, v.Elem())
+	case v.Kind() == reflect.Struct && !isCypherLeafType(v.Type()):
+		if data, ok := restEntityData(raw); ok {
+			raw = data
+		}
+		return decodeProperties(raw, v.Addr().Interface())
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8:
+		if isJsonNull(raw) {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, elem := range elems {
+			if err := decodeCypherValue(elem, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+	return decodePropertyValue(raw, v)
+}
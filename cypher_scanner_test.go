@@ -0,0 +1,107 @@
+package neo2go
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type scannedAddress struct {
+	City string `json:"city"`
+}
+
+type scannedUser struct {
+	Name    string          `neo:"n.name"`
+	Age     int             `neo:"n.age"`
+	Node    *NeoNode        `neo:"n"`
+	Address *scannedAddress `neo:"a"`
+	Ignored string          `neo:"-"`
+}
+
+const scannerTestResponse = `{
+	"columns": ["n.name", "n.age", "n", "a"],
+	"data": [
+		["Jon", 33, {"self": "http://localhost:7474/db/data/node/3", "data": {"name": "Jon"}}, {"self": "http://localhost:7474/db/data/node/4", "data": {"city": "Warsaw"}}],
+		["Ann", 29, {"self": "http://localhost:7474/db/data/node/5", "data": {"name": "Ann"}}, null]
+	]
+}`
+
+func TestScanAllIntoStructs(t *testing.T) {
+	var resp CypherResponse
+	if err := json.Unmarshal([]byte(scannerTestResponse), &resp); err != nil {
+		t.Fatalf("Could not decode the response: %v", err)
+	}
+
+	var users []*scannedUser
+	if err := resp.ScanAll(&users); err != nil {
+		t.Fatalf("Could not scan rows: %v", err)
+	}
+
+	if len(users) != 2 {
+		t.Fatalf("Expected 2 users, but got %d", len(users))
+	}
+	if users[0].Name != "Jon" || users[0].Age != 33 || users[0].Node.Id() != 3 {
+		t.Fatalf("Unexpected first user: %+v", users[0])
+	}
+	if users[0].Address == nil || users[0].Address.City != "Warsaw" {
+		t.Fatalf("Expected the address node properties to be decoded, but got %+v", users[0].Address)
+	}
+	if users[1].Name != "Ann" || users[1].Address != nil {
+		t.Fatalf("Unexpected second user: %+v", users[1])
+	}
+}
+
+func TestScanRowPositionally(t *testing.T) {
+	var resp CypherResponse
+	if err := json.Unmarshal([]byte(scannerTestResponse), &resp); err != nil {
+		t.Fatalf("Could not decode the response: %v", err)
+	}
+
+	rows := resp.Rows()
+	var names []string
+	for rows.Next() {
+		var name string
+		var age int
+		var node NeoNode
+		var address scannedAddress
+		if err := rows.Scan(&name, &age, &node, &address); err != nil {
+			t.Fatalf("Could not scan row: %v", err)
+		}
+		names = append(names, name)
+	}
+	if len(names) != 2 || names[1] != "Ann" {
+		t.Fatalf("Unexpected scanned values: %v", names)
+	}
+
+	var name string
+	if err := ScanCypherRow(resp.Columns, resp.Data[0], &name); err == nil {
+		t.Fatalf("Expected an error, when the number of destinations does not match the number of columns.")
+	}
+}
+
+func TestScanShortRowIntoStruct(t *testing.T) {
+	var resp CypherResponse
+	if err := json.Unmarshal([]byte(scannerTestResponse), &resp); err != nil {
+		t.Fatalf("Could not decode the response: %v", err)
+	}
+
+	var user scannedUser
+	if err := ScanCypherRow(resp.Columns, resp.Data[0][:2], &user); err == nil {
+		t.Fatalf("Expected an error, when the row is shorter than the columns.")
+	}
+}
+
+func TestScanSingleNodeColumnIntoStruct(t *testing.T) {
+	var result CypherResult
+	err := json.Unmarshal([]byte(`{"columns":["a"],"data":[{"rest":[{"self":"http://localhost:7474/db/data/node/4","data":{"city":"Oslo"}}]}]}`), &result)
+	if err != nil {
+		t.Fatalf("Could not decode the result: %v", err)
+	}
+
+	var addresses []scannedAddress
+	if err := result.ScanAll(&addresses); err != nil {
+		t.Fatalf("Could not scan rows: %v", err)
+	}
+	if len(addresses) != 1 || addresses[0].City != "Oslo" {
+		t.Fatalf("Unexpected scanned values: %+v", addresses)
+	}
+}
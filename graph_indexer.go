@@ -58,33 +58,37 @@ type GraphIndexer interface {
 	CreateUniqueRelationshipWithPropertiesOrFail(index *NeoIndex, key, value string, source *NeoNode, target *NeoNode, relType string, properties interface{}) (*NeoRelationship, *NeoResponse)
 
 	// 17.12.1
-	// FindNodeByExactMatchingAutoIndex(query string) (*[]*NeoNode, *NeoResponse)
+	FindNodeByExactMatchingAutoIndex(key, value string) (*[]*NeoNode, *NeoResponse)
+	FindRelationshipByExactMatchingAutoIndex(key, value string) (*[]*NeoRelationship, *NeoResponse)
 	// 17.12.2
-	// FindNodeByQueryingAutoIndex(query string) (*[]*NeoNode, *NeoResponse)
+	FindNodeByQueryingAutoIndex(query string) (*[]*NeoNode, *NeoResponse)
+	FindRelationshipByQueryingAutoIndex(query string) (*[]*NeoRelationship, *NeoResponse)
 
 	// 17.13.1 - autoindex configuration for Nodes
-	// CreateNodeAutoIndexWithConfiguration(name string, config interface{}) (*NeoIndex, *NeoResponse)
+	// (the auto-index is always named node_auto_index)
+	CreateNodeAutoIndexWithConfiguration(config interface{}) (*NeoIndex, *NeoResponse)
 	// 17.13.3
-	// GetNodeAutoIndexStatus() (bool, *NeoResponse)
+	GetNodeAutoIndexStatus() (*bool, *NeoResponse)
 	// 17.13.4
-	// SetNodeAutoIndexStatus(bool) *NeoResponse
+	SetNodeAutoIndexStatus(enabled bool) *NeoResponse
 	// 17.13.5
-	// GetNodeAutoIndexProperties() (*[]string, *NeoResponse)
+	GetNodeAutoIndexProperties() (*[]string, *NeoResponse)
 	// 17.13.6
-	// AddNodeAutoIndexProperty(propertyName string) *NeoResponse
+	AddNodeAutoIndexProperty(propertyName string) *NeoResponse
 	// 17.13.7
-	// DeleteNodeAutoIndexProperty(propertyName string) *NeoResponse
+	DeleteNodeAutoIndexProperty(propertyName string) *NeoResponse
 
 	// 17.13.2 - autoindex configuration for Relationships
-	// CreateRelationshipAutoIndexWithConfiguration(name string, config interface{}) (*NeoIndex, *NeoResponse)
+	// (the auto-index is always named relationship_auto_index)
+	CreateRelationshipAutoIndexWithConfiguration(config interface{}) (*NeoIndex, *NeoResponse)
 	// 17.13.3
-	// GetRelationshipAutoIndexStatus() (bool, *NeoResponse)
+	GetRelationshipAutoIndexStatus() (*bool, *NeoResponse)
 	// 17.13.4
-	// SetRelationshipAutoIndexStatus(bool) *NeoResponse
+	SetRelationshipAutoIndexStatus(enabled bool) *NeoResponse
 	// 17.13.5
-	// GetRelationshipAutoIndexProperties() (*[]string, *NeoResponse)
+	GetRelationshipAutoIndexProperties() (*[]string, *NeoResponse)
 	// 17.13.6
-	// AddRelationshipAutoIndexProperty(propertyName string) *NeoResponse
+	AddRelationshipAutoIndexProperty(propertyName string) *NeoResponse
 	// 17.13.7
-	// DeleteRelationshipAutoIndexProperty(propertyName string) *NeoResponse
+	DeleteRelationshipAutoIndexProperty(propertyName string) *NeoResponse
 }
@@ -0,0 +1,21 @@
+package neo2go
+
+// Contains methods for managing the schema (label/property indexes and constraints).
+type GraphSchemaManager interface {
+	// Fails with ErrIndexAlreadyExists, if the index exists (older servers report only the 409 status).
+	CreateSchemaIndex(label string, propertyKey string) (*SchemaIndex, *NeoResponse)
+	// Same as CreateSchemaIndex, but succeeds if the index already exists.
+	EnsureSchemaIndex(label string, propertyKey string) (*SchemaIndex, *NeoResponse)
+	GetSchemaIndexes(label string) (*[]*SchemaIndex, *NeoResponse)
+	GetAllSchemaIndexes() (*[]*SchemaIndex, *NeoResponse)
+	DropSchemaIndex(label string, propertyKey string) *NeoResponse
+
+	// Fails with ErrConstraintAlreadyExists, if the constraint exists (older servers report only the 409 status).
+	CreateUniquenessConstraint(label string, propertyKey string) (*SchemaConstraint, *NeoResponse)
+	// Same as CreateUniquenessConstraint, but succeeds if the constraint already exists.
+	EnsureUniquenessConstraint(label string, propertyKey string) (*SchemaConstraint, *NeoResponse)
+	GetUniquenessConstraints(label string) (*[]*SchemaConstraint, *NeoResponse)
+	GetConstraints(label string) (*[]*SchemaConstraint, *NeoResponse)
+	GetAllConstraints() (*[]*SchemaConstraint, *NeoResponse)
+	DropUniquenessConstraint(label string, propertyKey string) *NeoResponse
+}
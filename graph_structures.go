@@ -3,8 +3,11 @@ package neo2go
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"path"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // http://docs.neo4j.org/chunked/milestone/graphdb-neo4j-properties.html
@@ -36,6 +39,11 @@ type selfUrlAware interface {
 	SetSelf(url *UrlTemplate)
 }
 
+// Implemented by results, which can report errors in a successful (2xx) response.
+type resultErrorReporter interface {
+	resultError() error
+}
+
 func setTemplateIfNil(tmpl **UrlTemplate, val string) {
 	if *tmpl == nil {
 		newTempl := NewUrlTemplate(val)
@@ -43,6 +51,11 @@ func setTemplateIfNil(tmpl **UrlTemplate, val string) {
 	}
 }
 
+// The base URL is the URL of the data root (e.g. "http://localhost:7474/db/data/").
+func entityUrl(baseUrl string, collection string, id string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(baseUrl, "/"), collection, id)
+}
+
 type NeoNode struct {
 	AllRelationships           *UrlTemplate           `json:"all_relationships"`
 	AllTypedRelationships      *UrlTemplate           `json:"all_typed_relationships"`
@@ -60,10 +73,14 @@ type NeoNode struct {
 	Self                       *UrlTemplate           `json:"self"`
 	Traverse                   *UrlTemplate           `json:"traverse"`
 	batchId                    NeoBatchId
+	changes                    propertyChanges
 }
 
+// Decodes the properties into result. The numbers decoded into interface{} values
+// are json.Number values, so that longs do not lose their precision. The values of
+// the types with a PropertyCodec are decoded by the codec.
 func (n *NeoNode) ParseData(result interface{}) error {
-	return json.Unmarshal([]byte(n.Data), result)
+	return decodeProperties(n.Data, result)
 }
 
 func (n *NeoNode) Id() int64 {
@@ -109,6 +126,15 @@ func (n *NeoNode) setBatchId(bid NeoBatchId) {
 	n.SetDefaultUrlTemplates(fmt.Sprintf(`{%v}`, bid))
 }
 
+// Returns a node with the url templates built from the id and the base URL (the URL of the data root,
+// e.g. "http://localhost:7474/db/data/"), without fetching it. It can be used wherever an existing node
+// is expected (e.g. in batches, relationships or traversals), but its properties are not known.
+func NewNeoNodeFromId(baseUrl string, id int64) *NeoNode {
+	node := new(NeoNode)
+	node.SetDefaultUrlTemplates(entityUrl(baseUrl, "node", strconv.FormatInt(id, 10)))
+	return node
+}
+
 func (n *NeoNode) String() string {
 	return fmt.Sprintf("<Node id:%d>", n.Id())
 }
@@ -123,14 +149,23 @@ type NeoRelationship struct {
 	Type       string                 `json:"type"`
 	End        *UrlTemplate           `json:"end"`
 	batchId    NeoBatchId
+	changes    propertyChanges
 }
 
+// Decodes the properties into result (see NeoNode.ParseData).
 func (n *NeoRelationship) ParseData(result interface{}) error {
 	bytes, err := json.Marshal(n.Data)
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(bytes, result)
+	return decodeProperties(bytes, result)
+}
+
+type neoRelationshipFields NeoRelationship
+
+// Decodes the relationship, keeping the numbers in Data as json.Number values.
+func (n *NeoRelationship) UnmarshalJSON(data []byte) error {
+	return decodeJsonWithNumbers(data, (*neoRelationshipFields)(n))
 }
 
 func (n *NeoRelationship) Id() int64 {
@@ -155,19 +190,64 @@ func (n *NeoRelationship) IdOrBatchId() string {
 	return ""
 }
 
+func (n *NeoRelationship) SetDefaultUrlTemplates(id string) {
+	setTemplateIfNil(&n.Property, fmt.Sprintf(`%s/properties/{key}`, id))
+	setTemplateIfNil(&n.Self, fmt.Sprintf(`%s`, id))
+	setTemplateIfNil(&n.Properties, fmt.Sprintf(`%s/properties`, id))
+}
+
 func (n *NeoRelationship) setBatchId(bid NeoBatchId) {
 	n.batchId = bid
+	n.SetDefaultUrlTemplates(fmt.Sprintf(`{%v}`, bid))
+}
 
-	setTemplateIfNil(&n.Property, fmt.Sprintf(`{%v}/properties/{key}`, bid))
-	setTemplateIfNil(&n.Self, fmt.Sprintf(`{%v}`, bid))
-	setTemplateIfNil(&n.Properties, fmt.Sprintf(`{%v}/properties`, bid))
+// Returns a relationship with the url templates built from the id and the base URL (see NewNeoNodeFromId),
+// without fetching it. Its type, properties and nodes (Start and End) are not known.
+func NewNeoRelationshipFromId(baseUrl string, id int64) *NeoRelationship {
+	rel := new(NeoRelationship)
+	rel.SetDefaultUrlTemplates(entityUrl(baseUrl, "relationship", strconv.FormatInt(id, 10)))
+	return rel
+}
+
+type SchemaIndex struct {
+	Label        string   `json:"label"`
+	PropertyKeys []string `json:"property_keys"`
+}
+
+const SchemaConstraintUniqueness = "UNIQUENESS"
+
+type SchemaConstraint struct {
+	Label        string   `json:"label"`
+	Type         string   `json:"type"`
+	PropertyKeys []string `json:"property_keys"`
 }
 
 type NeoIndex struct {
 	Provider string
 	Template *UrlTemplate
 	Type     string
-	batchId  NeoBatchId
+	// The whole configuration of the index, as reported by the server.
+	Config  *LuceneIndexConfig
+	batchId NeoBatchId
+}
+
+func (n *NeoIndex) UnmarshalJSON(data []byte) error {
+	var fields struct {
+		Template *UrlTemplate `json:"template"`
+	}
+	config := new(LuceneIndexConfig)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return err
+	}
+
+	n.Template = fields.Template
+	n.Provider = config.Provider
+	n.Type = string(config.Type)
+	n.Config = config
+	return nil
 }
 
 func (n *NeoIndex) setBatchId(bid NeoBatchId) {
@@ -176,6 +256,23 @@ func (n *NeoIndex) setBatchId(bid NeoBatchId) {
 	setTemplateIfNil(&n.Template, fmt.Sprintf(`{%v}{key}/{value}`, bid))
 }
 
+// The kinds of the entities stored in a legacy index.
+type NeoIndexCategory string
+
+const (
+	NeoNodeIndex         NeoIndexCategory = "node"
+	NeoRelationshipIndex NeoIndexCategory = "relationship"
+)
+
+// Returns an index with the url template built from the name and the base URL (see NewNeoNodeFromId),
+// without fetching it. Its provider, type and configuration are not known.
+func NewNeoIndexFromName(baseUrl string, category NeoIndexCategory, name string) *NeoIndex {
+	index := new(NeoIndex)
+	indexUrl := entityUrl(baseUrl, "index/"+string(category), url.PathEscape(name))
+	setTemplateIfNil(&index.Template, indexUrl+`/{key}/{value}`)
+	return index
+}
+
 type NeoCodeSnippet struct {
 	Body     string `json:"body,omitempty"`
 	Language string `json:"language"`
@@ -328,26 +425,134 @@ func NewNeoPathFinderSpecWithRelationships(rels *NeoTraversalRelationship) *NeoP
 
 // Transactional Cypher
 
+// The format in which the server returns the rows of a transactional Cypher statement.
+type CypherResultDataContent string
+
+const (
+	// Plain values; nodes and relationships are returned as maps of their properties.
+	CypherRowContent CypherResultDataContent = "row"
+	// Nodes and relationships are returned in the REST format (see NeoNode and NeoRelationship).
+	CypherRestContent CypherResultDataContent = "REST"
+	// Nodes and relationships returned by the row, as a graph (see CypherGraph).
+	CypherGraphContent CypherResultDataContent = "graph"
+)
+
 type CypherTransactionRequest struct {
 	Cql    string
 	Params map[string]interface{}
+	// Defaults to CypherRestContent, when empty.
+	ResultDataContents []CypherResultDataContent
+	IncludeStats       bool
+}
+
+type CypherGraphNode struct {
+	Id         string          `json:"id"`
+	Labels     []string        `json:"labels"`
+	Properties json.RawMessage `json:"properties"`
+}
+
+func (c *CypherGraphNode) ParseProperties(result interface{}) error {
+	return decodeProperties(c.Properties, result)
+}
+
+type CypherGraphRelationship struct {
+	Id         string          `json:"id"`
+	Type       string          `json:"type"`
+	StartNode  string          `json:"startNode"`
+	EndNode    string          `json:"endNode"`
+	Properties json.RawMessage `json:"properties"`
+}
+
+func (c *CypherGraphRelationship) ParseProperties(result interface{}) error {
+	return decodeProperties(c.Properties, result)
+}
+
+type CypherGraph struct {
+	Nodes         []*CypherGraphNode         `json:"nodes"`
+	Relationships []*CypherGraphRelationship `json:"relationships"`
 }
 
 type CypherRow struct {
 	NeoRest []json.RawMessage `json:"rest"`
+	Row     []json.RawMessage `json:"row"`
+	Graph   *CypherGraph      `json:"graph"`
+	Meta    []json.RawMessage `json:"meta"`
+}
+
+// Returns the values of the row in the REST format or, if it was not requested, in the row format.
+func (c *CypherRow) Values() []json.RawMessage {
+	if c.NeoRest != nil {
+		return c.NeoRest
+	}
+	return c.Row
 }
 
 type CypherResult struct {
-	Columns []string    `json:"columns"`
-	Data    []CypherRow `json:"data"`
+	Columns []string     `json:"columns"`
+	Data    []CypherRow  `json:"data"`
+	Stats   *CypherStats `json:"stats"`
+	// Returned for statements prefixed with EXPLAIN or PROFILE.
+	Plan *CypherPlan `json:"plan"`
+}
+
+// Merges the graphs of all rows (requested with CypherGraphContent) into one graph,
+// in which every node and relationship appears only once.
+func (c *CypherResult) Graph() *CypherGraph {
+	graph := new(CypherGraph)
+	nodes := make(map[string]bool)
+	rels := make(map[string]bool)
+	for _, row := range c.Data {
+		if row.Graph == nil {
+			continue
+		}
+		for _, node := range row.Graph.Nodes {
+			if !nodes[node.Id] {
+				nodes[node.Id] = true
+				graph.Nodes = append(graph.Nodes, node)
+			}
+		}
+		for _, rel := range row.Graph.Relationships {
+			if !rels[rel.Id] {
+				rels[rel.Id] = true
+				graph.Relationships = append(graph.Relationships, rel)
+			}
+		}
+	}
+	return graph
+}
+
+type CypherTransactionInfo struct {
+	Expires string `json:"expires"`
 }
 
 type CypherTransaction struct {
-	Commit  *UrlTemplate   `json:"commit"`
-	Self    *UrlTemplate   `json:"self"`
-	Results []CypherResult `json:"results"`
+	Commit  *UrlTemplate           `json:"commit"`
+	Self    *UrlTemplate           `json:"self"`
+	Results []CypherResult         `json:"results"`
+	Info    *CypherTransactionInfo `json:"transaction"`
+	// The server reports the notifications for all the statements of a request together.
+	Notifications []CypherNotification `json:"notifications"`
+	Errors        []NeoError           `json:"errors"`
+	// The number of statements sent in the request.
+	statementCount int
 }
 
 func (c *CypherTransaction) SetSelf(url *UrlTemplate) {
 	c.Self = url
 }
+
+func (c *CypherTransaction) resultError() error {
+	if len(c.Errors) == 0 {
+		return nil
+	}
+	return newCypherTransactionError(c.Errors, len(c.Results), c.statementCount)
+}
+
+// Returns the time after which the server will roll back the transaction, unless it is used
+// (see GraphDatabaseService.ResetCypherTimeout).
+func (c *CypherTransaction) Expires() (time.Time, error) {
+	if c.Info == nil || c.Info.Expires == "" {
+		return time.Time{}, fmt.Errorf("The transaction does not have an expiration time.")
+	}
+	return time.Parse(time.RFC1123Z, c.Info.Expires)
+}
@@ -20,6 +20,12 @@ type Grapher interface {
 
 	AddLabel(node *NeoNode, label string) *NeoResponse
 	AddLabels(node *NeoNode, labels []string) *NeoResponse
+	ReplaceLabels(node *NeoNode, labels []string) *NeoResponse
+	RemoveLabel(node *NeoNode, label string) *NeoResponse
+	GetLabelsForNode(node *NeoNode) (*[]string, *NeoResponse)
+	GetNodesWithLabel(label string) (*[]*NeoNode, *NeoResponse)
+	GetNodesWithLabelAndProperty(label string, propertyKey string, propertyValue interface{}) (*[]*NeoNode, *NeoResponse)
+	GetAllLabels() (*[]string, *NeoResponse)
 
 	// ==============
 	// Node properties
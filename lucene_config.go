@@ -2,6 +2,10 @@ package neo2go
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type LuceneAnalyzerType string
@@ -11,18 +15,160 @@ const (
 	LuceneAnalyzerFullText LuceneAnalyzerType = "fulltext"
 )
 
+const LuceneIndexProvider = "lucene"
+
+// The configuration of a legacy index. ToLowerCase applies to both types: an exact index
+// with it stores the whole values in lower case. Neo4j ignores Type and ToLowerCase,
+// if an Analyzer (the name of a Lucene analyzer class) is set.
 type LuceneIndexConfig struct {
-	provider    string             `json:"provider"`
+	// Defaults to "lucene".
+	Provider    string             `json:"provider"`
 	Type        LuceneAnalyzerType `json:"type"`
 	ToLowerCase bool               `json:"to_lower_case"`
 	Analyzer    string             `json:"analyzer,omitempty"`
+	// The name of a Lucene similarity class, used for scoring the results.
+	Similarity string `json:"similarity,omitempty"`
 }
 
 func NewLuceneIndexConfig() *LuceneIndexConfig {
-	return &LuceneIndexConfig{"", LuceneAnalyzerExact, true, ""}
+	return &LuceneIndexConfig{Provider: LuceneIndexProvider, Type: LuceneAnalyzerExact, ToLowerCase: true}
+}
+
+func NewLuceneFullTextIndexConfig() *LuceneIndexConfig {
+	return &LuceneIndexConfig{Provider: LuceneIndexProvider, Type: LuceneAnalyzerFullText, ToLowerCase: true}
 }
 
+type luceneIndexConfigFields LuceneIndexConfig
+
 func (l *LuceneIndexConfig) MarshalJSON() ([]byte, error) {
-	l.provider = "lucene"
-	return json.Marshal(l)
+	fields := luceneIndexConfigFields(*l)
+	if fields.Provider == "" {
+		fields.Provider = LuceneIndexProvider
+	}
+	return json.Marshal(&fields)
+}
+
+// The server returns all the values of an index configuration as strings (e.g. "to_lower_case": "true").
+// An absent to_lower_case takes the default of Neo4j: false for exact indexes, and true for fulltext ones.
+func (l *LuceneIndexConfig) UnmarshalJSON(data []byte) error {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	*l = LuceneIndexConfig{}
+	l.Provider, _ = fields["provider"].(string)
+	if t, ok := fields["type"].(string); ok {
+		l.Type = LuceneAnalyzerType(t)
+	}
+	l.ToLowerCase = l.Type == LuceneAnalyzerFullText
+	l.Analyzer, _ = fields["analyzer"].(string)
+	l.Similarity, _ = fields["similarity"].(string)
+
+	switch toLowerCase := fields["to_lower_case"].(type) {
+	case bool:
+		l.ToLowerCase = toLowerCase
+	case string:
+		value, err := strconv.ParseBool(toLowerCase)
+		if err != nil {
+			return fmt.Errorf("Invalid to_lower_case value %q: %v", toLowerCase, err)
+		}
+		l.ToLowerCase = value
+	}
+	return nil
+}
+
+// Returns true, if the values are converted to lower case when they are indexed.
+func (l *LuceneIndexConfig) lowerCasesValues() bool {
+	switch l.Type {
+	case "", LuceneAnalyzerExact, LuceneAnalyzerFullText:
+		return l.Analyzer == "" && l.ToLowerCase
+	}
+	return false
+}
+
+// Returns the descriptions of the settings of the actual configuration, which differ from this one.
+// The settings, which are ignored by Neo4j (see LuceneIndexConfig), are not compared. A nil actual
+// configuration (e.g. of an index created with NewNeoIndexFromName) is unknown, so it is reported as different.
+func (l *LuceneIndexConfig) Diff(actual *LuceneIndexConfig) []string {
+	if actual == nil {
+		return []string{"configuration: unknown"}
+	}
+
+	var differences []string
+	compare := func(name string, expected, actual interface{}) {
+		if expected != actual {
+			differences = append(differences, fmt.Sprintf("%v: expected %v, but is %v", name, expected, actual))
+		}
+	}
+
+	provider, actualProvider := l.Provider, actual.Provider
+	if provider == "" {
+		provider = LuceneIndexProvider
+	}
+	if actualProvider == "" {
+		actualProvider = LuceneIndexProvider
+	}
+	compare("provider", provider, actualProvider)
+
+	if l.Analyzer != "" || actual.Analyzer != "" {
+		compare("analyzer", l.Analyzer, actual.Analyzer)
+	} else {
+		typ, actualType := l.Type, actual.Type
+		if typ == "" {
+			typ = LuceneAnalyzerExact
+		}
+		if actualType == "" {
+			actualType = LuceneAnalyzerExact
+		}
+		compare("type", typ, actualType)
+		compare("to_lower_case", l.ToLowerCase, actual.ToLowerCase)
+	}
+	compare("similarity", l.Similarity, actual.Similarity)
+	return differences
+}
+
+// Describes how an existing index differs from the desired configuration.
+type NeoIndexDrift struct {
+	Name string
+	// Set, if the index does not exist.
+	Missing     bool
+	Desired     *LuceneIndexConfig
+	Actual      *LuceneIndexConfig
+	Differences []string
+}
+
+func (n *NeoIndexDrift) String() string {
+	if n.Missing {
+		return fmt.Sprintf("The index %v does not exist.", n.Name)
+	}
+	return fmt.Sprintf("The index %v has a different configuration (%v).", n.Name, strings.Join(n.Differences, "; "))
+}
+
+// Compares the configurations of the existing indexes with the desired ones, by the index name.
+// The indexes, which are not in desired, are not reported.
+func DetectIndexDrift(desired map[string]*LuceneIndexConfig, actual map[string]*NeoIndex) []*NeoIndexDrift {
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var drifts []*NeoIndexDrift
+	for _, name := range names {
+		index := actual[name]
+		if index == nil {
+			drifts = append(drifts, &NeoIndexDrift{Name: name, Missing: true, Desired: desired[name]})
+			continue
+		}
+		if differences := desired[name].Diff(index.Config); len(differences) > 0 {
+			drifts = append(drifts, &NeoIndexDrift{
+				Name:        name,
+				Desired:     desired[name],
+				Actual:      index.Config,
+				Differences: differences,
+			})
+		}
+	}
+	return drifts
 }
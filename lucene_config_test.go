@@ -0,0 +1,77 @@
+package neo2go
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLuceneIndexConfigJSON(t *testing.T) {
+	data, err := json.Marshal(&LuceneIndexConfig{Type: LuceneAnalyzerFullText, Similarity: "org.example.Similarity"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"provider":"lucene","type":"fulltext","to_lower_case":false,"similarity":"org.example.Similarity"}`
+	if string(data) != expected {
+		t.Errorf("Expected %v, but got %v", expected, string(data))
+	}
+
+	var indexes map[string]*NeoIndex
+	err = json.Unmarshal([]byte(`{"people": {"template": "http://localhost:7474/db/data/index/node/people/{key}/{value}",
+		"provider": "lucene", "type": "fulltext", "to_lower_case": "false"}}`), &indexes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := indexes["people"]
+	if index.Provider != "lucene" || index.Type != "fulltext" || index.Template == nil {
+		t.Fatalf("Unexpected index: %+v", index)
+	}
+	if index.Config.ToLowerCase || index.Config.Type != LuceneAnalyzerFullText {
+		t.Errorf("Unexpected index configuration: %+v", index.Config)
+	}
+
+	var exact, fulltext LuceneIndexConfig
+	if err := json.Unmarshal([]byte(`{"provider": "lucene", "type": "exact"}`), &exact); err != nil || exact.ToLowerCase {
+		t.Errorf("Expected to_lower_case to default to false for an exact index, but got: %+v (%v)", exact, err)
+	}
+	if err := json.Unmarshal([]byte(`{"provider": "lucene", "type": "fulltext"}`), &fulltext); err != nil || !fulltext.ToLowerCase {
+		t.Errorf("Expected to_lower_case to default to true for a fulltext index, but got: %+v (%v)", fulltext, err)
+	}
+}
+
+func TestDetectIndexDrift(t *testing.T) {
+	actual := map[string]*NeoIndex{
+		"exact":    {Config: &LuceneIndexConfig{Provider: "lucene", Type: LuceneAnalyzerExact, ToLowerCase: false}},
+		"fulltext": {Config: &LuceneIndexConfig{Provider: "lucene", Type: LuceneAnalyzerFullText, ToLowerCase: false}},
+	}
+	desired := map[string]*LuceneIndexConfig{
+		"exact":    NewLuceneIndexConfig(),
+		"fulltext": NewLuceneFullTextIndexConfig(),
+		"missing":  NewLuceneIndexConfig(),
+	}
+
+	drifts := DetectIndexDrift(desired, actual)
+	if len(drifts) != 3 {
+		t.Fatalf("Expected 3 drifts, but got: %v", drifts)
+	}
+	for _, drift := range drifts[:2] {
+		if len(drift.Differences) != 1 || drift.Differences[0] != "to_lower_case: expected true, but is false" {
+			t.Errorf("Expected a to_lower_case difference, but got: %v", drift)
+		}
+	}
+	if drifts[0].Name != "exact" || drifts[1].Name != "fulltext" {
+		t.Errorf("Unexpected drifts: %v, %v", drifts[0], drifts[1])
+	}
+	if drifts[2].Name != "missing" || !drifts[2].Missing {
+		t.Errorf("Expected a missing index, but got: %v", drifts[2])
+	}
+
+	if differences := NewLuceneIndexConfig().Diff(NewLuceneIndexConfig()); len(differences) != 0 {
+		t.Errorf("Expected no differences, but got: %v", differences)
+	}
+
+	actual = map[string]*NeoIndex{"exact": NewNeoIndexFromName("http://localhost:7474/db/data/", NeoNodeIndex, "exact")}
+	drifts = DetectIndexDrift(map[string]*LuceneIndexConfig{"exact": NewLuceneIndexConfig()}, actual)
+	if len(drifts) != 1 || drifts[0].Missing || drifts[0].Actual != nil || len(drifts[0].Differences) != 1 {
+		t.Errorf("Expected an unknown configuration, but got: %v", drifts)
+	}
+}
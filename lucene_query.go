@@ -0,0 +1,184 @@
+package neo2go
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type luceneQueryKind uint8
+
+const (
+	luceneTermQuery luceneQueryKind = iota
+	lucenePhraseQuery
+	luceneWildcardQuery
+	luceneFuzzyQuery
+	luceneRangeQuery
+	luceneBooleanQuery
+	luceneNotQuery
+)
+
+// A Lucene query for the legacy indexes (see FindNodeByQuery and FindRelationshipByQuery),
+// which takes care of escaping the values.
+//
+//	query := LuceneAnd(LuceneTerm("name", "Clint Eastwood"), LuceneNot(LuceneWildcard("title", "Dirty*")))
+//	nodes, resp := service.FindNodeByQuery(index, query.String())
+type LuceneQuery struct {
+	kind     luceneQueryKind
+	text     string
+	value    string
+	boost    float64
+	children []*LuceneQuery
+}
+
+// The characters which have a special meaning in the query syntax.
+const luceneSpecialChars = `+-&|!(){}[]^"~*?:\/`
+
+// Escapes the special characters and the whitespace in the value, so that it is matched as a single term.
+func EscapeLuceneTerm(value string) string {
+	return escapeLucene(value, "")
+}
+
+func escapeLucene(value string, keep string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if (strings.ContainsRune(luceneSpecialChars, r) || unicode.IsSpace(r)) && !strings.ContainsRune(keep, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func luceneField(field string, query string) string {
+	if field == "" {
+		return query
+	}
+	return EscapeLuceneTerm(field) + ":" + query
+}
+
+// Matches the field with the exact value. If field is empty, the default field is used.
+func LuceneTerm(field, value string) *LuceneQuery {
+	return &LuceneQuery{kind: luceneTermQuery, value: value, text: luceneField(field, EscapeLuceneTerm(value))}
+}
+
+// Matches the words of the phrase in order; useful only with fulltext indexes.
+func LucenePhrase(field, phrase string) *LuceneQuery {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(phrase)
+	return &LuceneQuery{kind: lucenePhraseQuery, value: phrase, text: luceneField(field, `"`+escaped+`"`)}
+}
+
+// Matches the pattern, in which * stands for any characters, and ? for a single character.
+func LuceneWildcard(field, pattern string) *LuceneQuery {
+	return &LuceneQuery{kind: luceneWildcardQuery, value: pattern, text: luceneField(field, escapeLucene(pattern, "*?"))}
+}
+
+// Matches terms similar to the given one. The similarity must be between 0 and 1; 0 means the default.
+func LuceneFuzzy(field, term string, similarity float64) *LuceneQuery {
+	text := EscapeLuceneTerm(term) + "~"
+	if similarity > 0 {
+		text += strconv.FormatFloat(similarity, 'g', -1, 64)
+	}
+	return &LuceneQuery{kind: luceneFuzzyQuery, value: term, text: luceneField(field, text)}
+}
+
+// Matches the values between from and to (in lexicographic order). An empty bound is open (*).
+func LuceneRange(field, from, to string, inclusive bool) *LuceneQuery {
+	bound := func(value string) string {
+		if value == "" {
+			return "*"
+		}
+		return EscapeLuceneTerm(value)
+	}
+	left, right := "{", "}"
+	if inclusive {
+		left, right = "[", "]"
+	}
+	text := fmt.Sprintf("%s%s TO %s%s", left, bound(from), bound(to), right)
+	return &LuceneQuery{kind: luceneRangeQuery, value: from + to, text: luceneField(field, text)}
+}
+
+func luceneBoolean(operator string, queries []*LuceneQuery) *LuceneQuery {
+	parts := make([]string, len(queries))
+	for i, query := range queries {
+		parts[i] = query.nested()
+	}
+	return &LuceneQuery{kind: luceneBooleanQuery, text: strings.Join(parts, " "+operator+" "), children: queries}
+}
+
+// Matches, if all of the queries match.
+func LuceneAnd(queries ...*LuceneQuery) *LuceneQuery {
+	return luceneBoolean("AND", queries)
+}
+
+// Matches, if any of the queries matches.
+func LuceneOr(queries ...*LuceneQuery) *LuceneQuery {
+	return luceneBoolean("OR", queries)
+}
+
+// Matches, if the query does not match. Lucene does not support purely negative queries,
+// so it should be combined with other queries using LuceneAnd.
+func LuceneNot(query *LuceneQuery) *LuceneQuery {
+	return &LuceneQuery{kind: luceneNotQuery, text: "NOT " + query.nested(), children: []*LuceneQuery{query}}
+}
+
+// Returns a copy of the query, with its relevance multiplied by the boost.
+func (l *LuceneQuery) Boost(boost float64) *LuceneQuery {
+	boosted := *l
+	boosted.boost = boost
+	return &boosted
+}
+
+func (l *LuceneQuery) nested() string {
+	if l.kind == luceneBooleanQuery && l.boost == 0 {
+		return "(" + l.text + ")"
+	}
+	return l.String()
+}
+
+func (l *LuceneQuery) String() string {
+	if l.boost == 0 {
+		return l.text
+	}
+	text := l.text
+	if l.kind == luceneBooleanQuery || l.kind == luceneNotQuery {
+		text = "(" + text + ")"
+	}
+	return text + "^" + strconv.FormatFloat(l.boost, 'g', -1, 64)
+}
+
+// Returns warnings about the parts of the query, which will likely not work as expected
+// with an index using the given configuration.
+func (l *LuceneQuery) Warnings(config *LuceneIndexConfig) []string {
+	var warnings []string
+	l.collectWarnings(config, &warnings)
+	return warnings
+}
+
+func (l *LuceneQuery) collectWarnings(config *LuceneIndexConfig, warnings *[]string) {
+	switch l.kind {
+	case lucenePhraseQuery:
+		if config.Type == LuceneAnalyzerExact && strings.ContainsFunc(l.value, unicode.IsSpace) {
+			*warnings = append(*warnings, fmt.Sprintf("The phrase query %v is used with an exact index, "+
+				"which does not split the values into words.", l.text))
+		}
+	case luceneFuzzyQuery:
+		if config.Type == LuceneAnalyzerExact {
+			*warnings = append(*warnings, fmt.Sprintf("The fuzzy query %v is used with an exact index, "+
+				"where it matches whole values only.", l.text))
+		}
+	}
+
+	switch l.kind {
+	case luceneWildcardQuery, luceneFuzzyQuery, luceneRangeQuery:
+		if config.lowerCasesValues() && strings.ContainsFunc(l.value, unicode.IsUpper) {
+			*warnings = append(*warnings, fmt.Sprintf("The query %v is not analyzed, but it contains upper case letters, "+
+				"while the index stores lower case values.", l.text))
+		}
+	}
+
+	for _, child := range l.children {
+		child.collectWarnings(config, warnings)
+	}
+}
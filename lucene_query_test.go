@@ -0,0 +1,58 @@
+package neo2go
+
+import (
+	"testing"
+)
+
+func TestLuceneQueryRendering(t *testing.T) {
+	cases := []struct {
+		query    *LuceneQuery
+		expected string
+	}{
+		{LuceneTerm("name", "Clint Eastwood"), `name:Clint\ Eastwood`},
+		{LuceneTerm("", "a+b:c"), `a\+b\:c`},
+		{LucenePhrase("title", `The "Good" One`), `title:"The \"Good\" One"`},
+		{LuceneWildcard("name", "Cl?nt (E)*"), `name:Cl?nt\ \(E\)*`},
+		{LuceneFuzzy("name", "roam", 0), `name:roam~`},
+		{LuceneFuzzy("name", "roam", 0.8), `name:roam~0.8`},
+		{LuceneRange("year", "1990", "", true), `year:[1990 TO *]`},
+		{LuceneRange("year", "1990", "2000", false), `year:{1990 TO 2000}`},
+		{LuceneTerm("name", "Clint").Boost(2), `name:Clint^2`},
+		{
+			LuceneAnd(LuceneTerm("a", "1"), LuceneOr(LuceneTerm("b", "2"), LuceneTerm("c", "3")), LuceneNot(LuceneTerm("d", "4"))),
+			`a:1 AND (b:2 OR c:3) AND NOT d:4`,
+		},
+		{
+			LuceneOr(LuceneAnd(LuceneTerm("a", "1"), LuceneTerm("b", "2")).Boost(1.5), LuceneTerm("c", "3")),
+			`(a:1 AND b:2)^1.5 OR c:3`,
+		},
+	}
+
+	for _, c := range cases {
+		if actual := c.query.String(); actual != c.expected {
+			t.Errorf("Expected %v, but got %v", c.expected, actual)
+		}
+	}
+}
+
+func TestLuceneQueryWarnings(t *testing.T) {
+	query := LuceneAnd(LucenePhrase("title", "The Good One"), LuceneWildcard("name", "Cl*"), LuceneTerm("name", "Clint"))
+
+	if warnings := query.Warnings(NewLuceneIndexConfig()); len(warnings) != 2 {
+		t.Errorf("Expected 2 warnings for a lower case exact index, but got: %v", warnings)
+	}
+	if warnings := query.Warnings(NewLuceneFullTextIndexConfig()); len(warnings) != 1 {
+		t.Errorf("Expected 1 warning for a lower case fulltext index, but got: %v", warnings)
+	}
+
+	config := NewLuceneFullTextIndexConfig()
+	config.ToLowerCase = false
+	if warnings := query.Warnings(config); len(warnings) != 0 {
+		t.Errorf("Expected no warnings for a fulltext index, but got: %v", warnings)
+	}
+	config = NewLuceneIndexConfig()
+	config.ToLowerCase = false
+	if warnings := query.Warnings(config); len(warnings) != 1 {
+		t.Errorf("Expected 1 warning for an exact index, but got: %v", warnings)
+	}
+}
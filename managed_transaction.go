@@ -0,0 +1,99 @@
+package neo2go
+
+import (
+	"context"
+	"time"
+)
+
+// An open Cypher transaction, managed by GraphDatabaseService.WithTransaction.
+type ManagedCypherTransaction struct {
+	service     *GraphDatabaseService
+	trans       *CypherTransaction
+	lastFailure *NeoResponse
+}
+
+// Executes the statements within the transaction.
+func (m *ManagedCypherTransaction) Execute(requests ...*CypherTransactionRequest) (*CypherTransaction, *NeoResponse) {
+	result, resp := m.service.ExecuteCypher(m.trans, requests...)
+	m.update(result, resp)
+	return result, resp
+}
+
+// Executes the statements within the transaction, and streams their results (see ExecuteCypherStream).
+func (m *ManagedCypherTransaction) ExecuteStream(requests ...*CypherTransactionRequest) (*NeoResultStream, *NeoResponse) {
+	stream, resp := m.service.ExecuteCypherStream(m.trans, requests...)
+	if !resp.Ok() {
+		m.lastFailure = resp
+	}
+	return stream, resp
+}
+
+// Returns the time after which the server will roll back the transaction, unless it is used.
+func (m *ManagedCypherTransaction) Expires() (time.Time, error) {
+	return m.trans.Expires()
+}
+
+// Resets the transaction timeout; useful when there is a long running work between statements.
+func (m *ManagedCypherTransaction) ResetTimeout() *NeoResponse {
+	result, resp := m.service.ResetCypherTimeout(m.trans)
+	m.update(result, resp)
+	return resp
+}
+
+func (m *ManagedCypherTransaction) update(result *CypherTransaction, resp *NeoResponse) {
+	if !resp.Ok() {
+		m.lastFailure = resp
+		return
+	}
+	if result.Self == nil {
+		result.Self = m.trans.Self
+	}
+	m.trans = result
+}
+
+func (m *ManagedCypherTransaction) rollback() {
+	// The transaction has to be rolled back, even if the context of the service is already done.
+	service := m.service.WithContext(context.WithoutCancel(m.service.Context()))
+	service.RollbackCypher(m.trans)
+}
+
+// Begins a transaction and passes it to the work function. If the function returns without an error,
+// the transaction is committed. Otherwise (or if the function panics), the transaction is rolled back.
+//
+// If the work function returns an error from a failed statement (the NeoResponse.Err value),
+// the returned response is the response of that statement. Other errors are returned as local errors.
+//
+// The whole transaction is executed again, when it fails with a transient error and the service has
+// a retry policy (see RetryCypherTransaction), so the work function should not have side effects
+// besides the executed statements.
+func (g *GraphDatabaseService) WithTransaction(work func(tx *ManagedCypherTransaction) error) *NeoResponse {
+	return g.RetryCypherTransaction(func() *NeoResponse {
+		return g.runTransaction(work)
+	})
+}
+
+func (g *GraphDatabaseService) runTransaction(work func(tx *ManagedCypherTransaction) error) *NeoResponse {
+	trans, resp := g.NewCypherTransaction()
+	if !resp.Ok() {
+		return resp
+	}
+
+	tx := &ManagedCypherTransaction{service: g, trans: trans}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.rollback()
+			panic(p)
+		}
+	}()
+
+	if err := work(tx); err != nil {
+		tx.rollback()
+		if tx.lastFailure != nil && tx.lastFailure.Err == err {
+			return tx.lastFailure
+		}
+		return NewLocalErrorResponse(200, err)
+	}
+
+	_, resp = g.CommitCypher(tx.trans)
+	return resp
+}
@@ -0,0 +1,234 @@
+package neo2go
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrMigrationChecksumMismatch = errors.New("The migration has been changed after it was applied.")
+	ErrMigrationMissing          = errors.New("The applied migration does not exist.")
+)
+
+// A set of Cypher statements, identified by its version.
+type Migration struct {
+	Version    int64
+	Name       string
+	Statements []string
+	// The hex encoded SHA-256 of the migration file.
+	Checksum string
+}
+
+// A migration recorded in the graph.
+type AppliedMigration struct {
+	Version   int64  `neo:"version"`
+	Name      string `neo:"name"`
+	Checksum  string `neo:"checksum"`
+	AppliedAt int64  `neo:"appliedAt"`
+}
+
+// Reads the migrations from the files in dir, named <version>_<name>.cypher (e.g. 0001_create_indexes.cypher).
+// The statements in a file are separated by a semicolon at the end of a line;
+// lines starting with // are ignored.
+func ReadMigrations(dir string) ([]*Migration, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.cypher"))
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]*Migration, 0, len(paths))
+	versions := make(map[int64]string)
+	for _, path := range paths {
+		migration, err := readMigration(path)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := versions[migration.Version]; ok {
+			return nil, fmt.Errorf("The migrations %v and %v have the same version.", other, path)
+		}
+		versions[migration.Version] = path
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func readMigration(path string) (*Migration, error) {
+	name := strings.TrimSuffix(filepath.Base(path), ".cypher")
+	parts := strings.SplitN(name, "_", 2)
+	version, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("The name of the migration %v does not start with a version: %v", path, err)
+	}
+	migration := &Migration{Version: version}
+	if len(parts) == 2 {
+		migration.Name = parts[1]
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	checksum := sha256.Sum256(content)
+	migration.Checksum = hex.EncodeToString(checksum[:])
+	migration.Statements = splitCypherStatements(string(content))
+	return migration, nil
+}
+
+func splitCypherStatements(content string) []string {
+	var statements []string
+	var current []string
+	flush := func() {
+		if statement := strings.TrimSpace(strings.Join(current, "\n")); statement != "" {
+			statements = append(statements, statement)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		if strings.HasSuffix(trimmed, ";") {
+			current = append(current, strings.TrimSuffix(trimmed, ";"))
+			flush()
+		} else {
+			current = append(current, line)
+		}
+	}
+	flush()
+	return statements
+}
+
+// Applies migrations, and records the applied versions as nodes in the graph.
+type Migrator struct {
+	service *GraphDatabaseService
+	// The label of the nodes recording the applied migrations. Defaults to "SchemaMigration".
+	Label string
+	// If set, Migrate only returns the pending migrations, without applying them.
+	DryRun bool
+}
+
+func NewMigrator(service *GraphDatabaseService) *Migrator {
+	return &Migrator{service: service, Label: "SchemaMigration"}
+}
+
+// Returns the migrations recorded in the graph, ordered by version.
+func (m *Migrator) Applied() ([]*AppliedMigration, *NeoResponse) {
+	cql := fmt.Sprintf("MATCH (m:`%s`) RETURN m.version AS version, m.name AS name, m.checksum AS checksum, "+
+		"m.appliedAt AS appliedAt ORDER BY m.version", m.Label)
+	result, resp := m.service.CypherAutoCommit(&CypherTransactionRequest{
+		Cql:                cql,
+		ResultDataContents: []CypherResultDataContent{CypherRowContent},
+	})
+	if !resp.Ok() {
+		return nil, resp
+	}
+
+	var applied []*AppliedMigration
+	if err := result.Results[0].ScanAll(&applied); err != nil {
+		return nil, NewLocalErrorResponse(resp.ExpectedCode, err)
+	}
+	return applied, resp
+}
+
+// Checks that every applied migration exists, and has not been changed since it was applied.
+func (m *Migrator) Verify(migrations []*Migration) *NeoResponse {
+	applied, resp := m.Applied()
+	if !resp.Ok() {
+		return resp
+	}
+	if err := verifyMigrations(migrations, applied); err != nil {
+		return NewLocalErrorResponse(resp.ExpectedCode, err)
+	}
+	return resp
+}
+
+func verifyMigrations(migrations []*Migration, applied []*AppliedMigration) error {
+	byVersion := make(map[int64]*Migration)
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+	for _, appliedMigration := range applied {
+		migration, ok := byVersion[appliedMigration.Version]
+		if !ok {
+			return fmt.Errorf("%w (version %d)", ErrMigrationMissing, appliedMigration.Version)
+		}
+		if migration.Checksum != appliedMigration.Checksum {
+			return fmt.Errorf("%w (version %d)", ErrMigrationChecksumMismatch, appliedMigration.Version)
+		}
+	}
+	return nil
+}
+
+// Returns the migrations which have not been applied yet.
+func (m *Migrator) Pending(migrations []*Migration) ([]*Migration, *NeoResponse) {
+	applied, resp := m.Applied()
+	if !resp.Ok() {
+		return nil, resp
+	}
+	if err := verifyMigrations(migrations, applied); err != nil {
+		return nil, NewLocalErrorResponse(resp.ExpectedCode, err)
+	}
+
+	appliedVersions := make(map[int64]bool)
+	for _, appliedMigration := range applied {
+		appliedVersions[appliedMigration.Version] = true
+	}
+	var pending []*Migration
+	for _, migration := range migrations {
+		if !appliedVersions[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, resp
+}
+
+// Verifies the applied migrations, and applies the pending ones in order.
+// Returns the applied migrations (or the ones which would be applied, in the dry-run mode).
+//
+// The statements of a migration are executed in one transaction, and the migration is recorded in
+// another one, since Neo4j does not allow schema changes and data changes in the same transaction.
+func (m *Migrator) Migrate(migrations []*Migration) ([]*Migration, *NeoResponse) {
+	pending, resp := m.Pending(migrations)
+	if !resp.Ok() || m.DryRun {
+		return pending, resp
+	}
+
+	for i, migration := range pending {
+		if len(migration.Statements) > 0 {
+			requests := make([]*CypherTransactionRequest, len(migration.Statements))
+			for j, statement := range migration.Statements {
+				requests[j] = &CypherTransactionRequest{Cql: statement}
+			}
+			if _, resp = m.service.CypherAutoCommit(requests...); !resp.Ok() {
+				return pending[:i], resp
+			}
+		}
+
+		_, resp = m.service.CypherAutoCommit(&CypherTransactionRequest{
+			Cql: fmt.Sprintf("MERGE (m:`%s` {version: {version}}) SET m.name = {name}, m.checksum = {checksum}, "+
+				"m.appliedAt = timestamp()", m.Label),
+			Params: map[string]interface{}{
+				"version":  migration.Version,
+				"name":     migration.Name,
+				"checksum": migration.Checksum,
+			},
+		})
+		if !resp.Ok() {
+			return pending[:i], resp
+		}
+	}
+	return pending, resp
+}
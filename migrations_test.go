@@ -0,0 +1,100 @@
+package neo2go
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeMigrationFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReadMigrations(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"0010_people.cypher": "// People\nCREATE INDEX ON :Person(name);\nCREATE CONSTRAINT ON (p:Person)\n  ASSERT p.email IS UNIQUE;\n",
+		"0002_init.cypher":   "CREATE (:Root)",
+		"README.md":          "ignored",
+	})
+
+	migrations, err := ReadMigrations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 2 || migrations[1].Name != "people" {
+		t.Fatalf("Unexpected migrations: %v", migrations)
+	}
+	statements := migrations[1].Statements
+	if len(statements) != 2 || statements[0] != "CREATE INDEX ON :Person(name)" || !strings.HasSuffix(statements[1], "ASSERT p.email IS UNIQUE") {
+		t.Fatalf("Unexpected statements: %q", statements)
+	}
+	if len(migrations[0].Checksum) != 64 || migrations[0].Checksum == migrations[1].Checksum {
+		t.Fatalf("Unexpected checksums: %v, %v", migrations[0].Checksum, migrations[1].Checksum)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"1_init.cypher":   "CREATE (:Root)",
+		"2_people.cypher": "CREATE INDEX ON :Person(name);",
+	})
+	migrations, err := ReadMigrations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appliedChecksum := migrations[0].Checksum
+	var statements []string
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Statements []struct{ Statement string } `json:"statements"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		for _, statement := range body.Statements {
+			statements = append(statements, statement.Statement)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(body.Statements[0].Statement, "MATCH") {
+			fmt.Fprintf(w, `{"results":[{"columns":["version","name","checksum","appliedAt"],"data":[{"row":[1,"init","%s",1]}]}],"errors":[]}`, appliedChecksum)
+		} else {
+			fmt.Fprint(w, `{"results":[],"errors":[]}`)
+		}
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+	migrator := NewMigrator(service)
+
+	migrator.DryRun = true
+	pending, resp := migrator.Migrate(migrations)
+	checkResponseSucceeded(t, resp, 200)
+	if len(pending) != 1 || pending[0].Version != 2 || len(statements) != 1 {
+		t.Fatalf("Expected one pending migration, and no changes, but got: %v (%q)", pending, statements)
+	}
+
+	migrator.DryRun = false
+	statements = nil
+	applied, resp := migrator.Migrate(migrations)
+	checkResponseSucceeded(t, resp, 200)
+	if len(applied) != 1 || len(statements) != 3 || statements[1] != "CREATE INDEX ON :Person(name)" ||
+		!strings.HasPrefix(statements[2], "MERGE (m:`SchemaMigration` {version: {version}})") {
+		t.Fatalf("Unexpected migration: %v (%q)", applied, statements)
+	}
+
+	appliedChecksum = "changed"
+	if resp = migrator.Verify(migrations); !errors.Is(resp.Err, ErrMigrationChecksumMismatch) {
+		t.Fatalf("Expected %v, but got: %v", ErrMigrationChecksumMismatch, resp.Err)
+	}
+}
@@ -2,11 +2,14 @@ package neo2go
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
+	"time"
 )
 
 const (
@@ -17,6 +20,7 @@ var _ Grapher = (*GraphDatabaseService)(nil)
 var _ GraphIndexer = (*GraphDatabaseService)(nil)
 var _ GraphPathFinder = (*GraphDatabaseService)(nil)
 var _ GraphTraverser = (*GraphDatabaseService)(nil)
+var _ GraphSchemaManager = (*GraphDatabaseService)(nil)
 
 var jsonContentTypeRegExp *regexp.Regexp
 
@@ -25,10 +29,15 @@ func init() {
 }
 
 type GraphDatabaseService struct {
-	client           *http.Client
-	builder          *neoRequestBuilder
-	maxConnChannel   chan int
-	basicAuthPayload string
+	client              *http.Client
+	builder             *neoRequestBuilder
+	maxConnChannel      chan int
+	basicAuthPayload    string
+	ctx                 context.Context
+	requestTimeout      time.Duration
+	defaultHeaders      http.Header
+	retryPolicy         *RetryPolicy
+	notificationHandler CypherNotificationHandler
 }
 
 func NewGraphDatabaseService() *GraphDatabaseService {
@@ -36,14 +45,48 @@ func NewGraphDatabaseService() *GraphDatabaseService {
 }
 
 func NewGraphDatabaseServiceWithMaxConn(maxConn uint) *GraphDatabaseService {
+	return NewGraphDatabaseServiceWithOptions(WithMaxConn(maxConn))
+}
+
+func NewGraphDatabaseServiceWithOptions(options ...ServiceOption) *GraphDatabaseService {
+	config := serviceConfig{maxConn: 10, headers: make(http.Header)}
+	for _, option := range options {
+		option(&config)
+	}
+
 	service := GraphDatabaseService{
-		client:         &http.Client{},
-		builder:        &neoRequestBuilder{root: &NeoRoot{}, dataRoot: &NeoDataRoot{}, self: &UrlTemplate{}},
-		maxConnChannel: make(chan int, maxConn),
+		client:              config.httpClient(),
+		builder:             &neoRequestBuilder{root: &NeoRoot{}, dataRoot: &NeoDataRoot{}, self: &UrlTemplate{}},
+		maxConnChannel:      make(chan int, config.maxConn),
+		basicAuthPayload:    config.basicAuth,
+		requestTimeout:      config.requestTimeout,
+		defaultHeaders:      config.headers,
+		retryPolicy:         config.retryPolicy,
+		notificationHandler: config.notificationHandler,
+	}
+	return &service
+}
+
+// Returns a shallow copy of the service, which executes all its requests within the given context.
+// The copy shares the connection, configuration and the connection limit with the original service.
+// Cancelling the context aborts the request in flight, or stops waiting for a free connection slot.
+func (g *GraphDatabaseService) WithContext(ctx context.Context) *GraphDatabaseService {
+	if ctx == nil {
+		panic("neo2go: nil context")
 	}
+	service := *g
+	service.ctx = ctx
 	return &service
 }
 
+// Returns the context used by the service. If none was set, context.Background() is returned.
+func (g *GraphDatabaseService) Context() context.Context {
+	if g.ctx != nil {
+		return g.ctx
+	}
+	return context.Background()
+}
+
 func (g *GraphDatabaseService) SetBasicAuth(username, password string) {
 	g.basicAuthPayload = base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
 }
@@ -85,22 +128,33 @@ func (g *GraphDatabaseService) Cypher(cql string, params map[string]interface{})
 
 func (g *GraphDatabaseService) CypherAutoCommit(requests ...*CypherTransactionRequest) (*CypherTransaction, *NeoResponse) {
 	result, reqData := g.builder.TransactionalCypher(nil, true, requests...)
-	return result, g.executeFromRequestData(reqData)
+	return result, g.executeCypherFromRequestData(result, reqData)
 }
 
 func (g *GraphDatabaseService) NewCypherTransaction(requests ...*CypherTransactionRequest) (*CypherTransaction, *NeoResponse) {
 	result, reqData := g.builder.TransactionalCypher(nil, false, requests...)
-	return result, g.executeFromRequestData(reqData)
+	return result, g.executeCypherFromRequestData(result, reqData)
 }
 
 func (g *GraphDatabaseService) ExecuteCypher(cypherTrans *CypherTransaction, requests ...*CypherTransactionRequest) (*CypherTransaction, *NeoResponse) {
 	result, reqData := g.builder.TransactionalCypher(cypherTrans, false, requests...)
-	return result, g.executeFromRequestData(reqData)
+	return result, g.executeCypherFromRequestData(result, reqData)
 }
 
 func (g *GraphDatabaseService) CommitCypher(cypherTrans *CypherTransaction, requests ...*CypherTransactionRequest) (*CypherTransaction, *NeoResponse) {
 	result, reqData := g.builder.TransactionalCypher(cypherTrans, true, requests...)
-	return result, g.executeFromRequestData(reqData)
+	return result, g.executeCypherFromRequestData(result, reqData)
+}
+
+func (g *GraphDatabaseService) RollbackCypher(cypherTrans *CypherTransaction) *NeoResponse {
+	reqData := g.builder.RollbackCypherTransaction(cypherTrans)
+	return g.executeFromRequestData(reqData)
+}
+
+// Keeps the transaction alive, by executing an empty list of statements.
+// The returned transaction contains the new expiration time.
+func (g *GraphDatabaseService) ResetCypherTimeout(cypherTrans *CypherTransaction) (*CypherTransaction, *NeoResponse) {
+	return g.ExecuteCypher(cypherTrans)
 }
 
 // Grapher interface
@@ -130,6 +184,39 @@ func (g *GraphDatabaseService) AddLabels(node *NeoNode, labels []string) *NeoRes
 	return g.executeFromRequestData(reqData)
 }
 
+func (g *GraphDatabaseService) ReplaceLabels(node *NeoNode, labels []string) *NeoResponse {
+	reqData := g.builder.ReplaceLabels(node, labels)
+	return g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) RemoveLabel(node *NeoNode, label string) *NeoResponse {
+	reqData := g.builder.RemoveLabel(node, label)
+	return g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) GetLabelsForNode(node *NeoNode) (*[]string, *NeoResponse) {
+	result, reqData := g.builder.GetLabelsForNode(node)
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) GetNodesWithLabel(label string) (*[]*NeoNode, *NeoResponse) {
+	result, reqData := g.builder.GetNodesWithLabel(label)
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) GetNodesWithLabelAndProperty(label string, propertyKey string, propertyValue interface{}) (*[]*NeoNode, *NeoResponse) {
+	result, reqData, err := g.builder.GetNodesWithLabelAndProperty(label, propertyKey, propertyValue)
+	if err != nil {
+		return result, NewLocalErrorResponse(200, err)
+	}
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) GetAllLabels() (*[]string, *NeoResponse) {
+	result, reqData := g.builder.GetAllLabels()
+	return result, g.executeFromRequestData(reqData)
+}
+
 func (g *GraphDatabaseService) GetNode(uri string) (*NeoNode, *NeoResponse) {
 	result, reqData := g.builder.GetNode(uri)
 	return result, g.executeFromRequestData(reqData)
@@ -284,6 +371,15 @@ func (g *GraphDatabaseService) GetNodeIndexes() (*map[string]*NeoIndex, *NeoResp
 	return result, g.executeFromRequestData(reqData)
 }
 
+// Compares the configurations of the node indexes with the desired ones (see DetectIndexDrift).
+func (g *GraphDatabaseService) GetNodeIndexDrift(desired map[string]*LuceneIndexConfig) ([]*NeoIndexDrift, *NeoResponse) {
+	indexes, resp := g.GetNodeIndexes()
+	if !resp.Ok() {
+		return nil, resp
+	}
+	return DetectIndexDrift(desired, *indexes), resp
+}
+
 // 17.10.5
 func (g *GraphDatabaseService) AddNodeToIndex(index *NeoIndex, node *NeoNode, key, value string) (*NeoNode, *NeoResponse) {
 	result, reqData, err := g.builder.AddNodeToIndex(index, node, key, value)
@@ -356,6 +452,15 @@ func (g *GraphDatabaseService) GetRelationshipIndexes() (*map[string]*NeoIndex,
 	return result, g.executeFromRequestData(reqData)
 }
 
+// Compares the configurations of the relationship indexes with the desired ones (see DetectIndexDrift).
+func (g *GraphDatabaseService) GetRelationshipIndexDrift(desired map[string]*LuceneIndexConfig) ([]*NeoIndexDrift, *NeoResponse) {
+	indexes, resp := g.GetRelationshipIndexes()
+	if !resp.Ok() {
+		return nil, resp
+	}
+	return DetectIndexDrift(desired, *indexes), resp
+}
+
 // 17.10.5
 func (g *GraphDatabaseService) AddRelationshipToIndex(index *NeoIndex, rel *NeoRelationship, key, value string) (*NeoRelationship, *NeoResponse) {
 	result, reqData, err := g.builder.AddRelationshipToIndex(index, rel, key, value)
@@ -478,6 +583,88 @@ func (g *GraphDatabaseService) CreateUniqueRelationshipWithPropertiesOrFail(inde
 	return result, g.executeFromRequestData(reqData)
 }
 
+// Auto-indexes
+
+func (g *GraphDatabaseService) FindNodeByExactMatchingAutoIndex(key, value string) (*[]*NeoNode, *NeoResponse) {
+	result, reqData := g.builder.FindNodeByExactMatchingAutoIndex(key, value)
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) FindNodeByQueryingAutoIndex(query string) (*[]*NeoNode, *NeoResponse) {
+	result, reqData := g.builder.FindNodeByQueryingAutoIndex(query)
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) CreateNodeAutoIndexWithConfiguration(config interface{}) (*NeoIndex, *NeoResponse) {
+	result, reqData := g.builder.CreateNodeAutoIndexWithConfiguration(config)
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) GetNodeAutoIndexStatus() (*bool, *NeoResponse) {
+	result, reqData := g.builder.GetNodeAutoIndexStatus()
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) SetNodeAutoIndexStatus(enabled bool) *NeoResponse {
+	reqData := g.builder.SetNodeAutoIndexStatus(enabled)
+	return g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) GetNodeAutoIndexProperties() (*[]string, *NeoResponse) {
+	result, reqData := g.builder.GetNodeAutoIndexProperties()
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) AddNodeAutoIndexProperty(propertyName string) *NeoResponse {
+	reqData := g.builder.AddNodeAutoIndexProperty(propertyName)
+	return g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) DeleteNodeAutoIndexProperty(propertyName string) *NeoResponse {
+	reqData := g.builder.DeleteNodeAutoIndexProperty(propertyName)
+	return g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) FindRelationshipByExactMatchingAutoIndex(key, value string) (*[]*NeoRelationship, *NeoResponse) {
+	result, reqData := g.builder.FindRelationshipByExactMatchingAutoIndex(key, value)
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) FindRelationshipByQueryingAutoIndex(query string) (*[]*NeoRelationship, *NeoResponse) {
+	result, reqData := g.builder.FindRelationshipByQueryingAutoIndex(query)
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) CreateRelationshipAutoIndexWithConfiguration(config interface{}) (*NeoIndex, *NeoResponse) {
+	result, reqData := g.builder.CreateRelationshipAutoIndexWithConfiguration(config)
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) GetRelationshipAutoIndexStatus() (*bool, *NeoResponse) {
+	result, reqData := g.builder.GetRelationshipAutoIndexStatus()
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) SetRelationshipAutoIndexStatus(enabled bool) *NeoResponse {
+	reqData := g.builder.SetRelationshipAutoIndexStatus(enabled)
+	return g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) GetRelationshipAutoIndexProperties() (*[]string, *NeoResponse) {
+	result, reqData := g.builder.GetRelationshipAutoIndexProperties()
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) AddRelationshipAutoIndexProperty(propertyName string) *NeoResponse {
+	reqData := g.builder.AddRelationshipAutoIndexProperty(propertyName)
+	return g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) DeleteRelationshipAutoIndexProperty(propertyName string) *NeoResponse {
+	reqData := g.builder.DeleteRelationshipAutoIndexProperty(propertyName)
+	return g.executeFromRequestData(reqData)
+}
+
 // GraphTraverser
 
 // 17.14.1+
@@ -524,7 +711,7 @@ func (g *GraphDatabaseService) TraverseByNodesWithPaging(traversal *NeoTraversal
 		pagedTraverser := &NeoPagedTraverser{response.location}
 		return pagedTraverser, *result, response
 	}
-	return nil, nil, NewLocalErrorResponse(reqData.expectedStatus, fmt.Errorf("The server did not return traverser's location."))
+	return nil, nil, NewLocalErrorResponse(reqData.expectedStatus, ErrMissingLocation)
 }
 
 func (g *GraphDatabaseService) TraverseByRelationshipsWithPaging(traversal *NeoTraversal, start *NeoNode) (*NeoPagedTraverser, []*NeoRelationship, *NeoResponse) {
@@ -537,7 +724,7 @@ func (g *GraphDatabaseService) TraverseByRelationshipsWithPaging(traversal *NeoT
 		pagedTraverser := &NeoPagedTraverser{response.location}
 		return pagedTraverser, *result, response
 	}
-	return nil, nil, NewLocalErrorResponse(reqData.expectedStatus, fmt.Errorf("The server did not return traverser's location."))
+	return nil, nil, NewLocalErrorResponse(reqData.expectedStatus, ErrMissingLocation)
 }
 
 func (g *GraphDatabaseService) TraverseByPathsWithPaging(traversal *NeoTraversal, start *NeoNode) (*NeoPagedTraverser, []*NeoPath, *NeoResponse) {
@@ -550,7 +737,7 @@ func (g *GraphDatabaseService) TraverseByPathsWithPaging(traversal *NeoTraversal
 		pagedTraverser := &NeoPagedTraverser{response.location}
 		return pagedTraverser, *result, response
 	}
-	return nil, nil, NewLocalErrorResponse(reqData.expectedStatus, fmt.Errorf("The server did not return traverser's location."))
+	return nil, nil, NewLocalErrorResponse(reqData.expectedStatus, ErrMissingLocation)
 }
 
 func (g *GraphDatabaseService) TraverseByFullPathsWithPaging(traversal *NeoTraversal, start *NeoNode) (*NeoPagedTraverser, []*NeoFullPath, *NeoResponse) {
@@ -563,7 +750,7 @@ func (g *GraphDatabaseService) TraverseByFullPathsWithPaging(traversal *NeoTrave
 		pagedTraverser := &NeoPagedTraverser{response.location}
 		return pagedTraverser, *result, response
 	}
-	return nil, nil, NewLocalErrorResponse(reqData.expectedStatus, fmt.Errorf("The server did not return traverser's location."))
+	return nil, nil, NewLocalErrorResponse(reqData.expectedStatus, ErrMissingLocation)
 }
 
 // 17.14.6+
@@ -587,6 +774,103 @@ func (g *GraphDatabaseService) TraverseByFullPathsGetNextPage(traverser *NeoPage
 	return *result, g.executeFromRequestData(reqData)
 }
 
+// GraphSchemaManager
+
+func (g *GraphDatabaseService) CreateSchemaIndex(label string, propertyKey string) (*SchemaIndex, *NeoResponse) {
+	result, reqData := g.builder.CreateSchemaIndex(label, propertyKey)
+	return result, alreadyExistsResponse(g.executeFromRequestData(reqData), ErrIndexAlreadyExists)
+}
+
+// Creates the index, or returns the existing one. A conflict is resolved only if the index is found
+// (e.g. not when the property is already indexed by a constraint); otherwise the conflict is returned.
+func (g *GraphDatabaseService) EnsureSchemaIndex(label string, propertyKey string) (*SchemaIndex, *NeoResponse) {
+	result, resp := g.CreateSchemaIndex(label, propertyKey)
+	if !isConflict(resp, ErrIndexAlreadyExists) {
+		return result, resp
+	}
+	indexes, getResp := g.GetSchemaIndexes(label)
+	if getResp.Ok() {
+		for _, index := range *indexes {
+			if len(index.PropertyKeys) == 1 && index.PropertyKeys[0] == propertyKey {
+				return index, getResp
+			}
+		}
+	}
+	return result, resp
+}
+
+func (g *GraphDatabaseService) GetSchemaIndexes(label string) (*[]*SchemaIndex, *NeoResponse) {
+	result, reqData := g.builder.GetSchemaIndexes(label)
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) GetAllSchemaIndexes() (*[]*SchemaIndex, *NeoResponse) {
+	result, reqData := g.builder.GetAllSchemaIndexes()
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) DropSchemaIndex(label string, propertyKey string) *NeoResponse {
+	reqData := g.builder.DropSchemaIndex(label, propertyKey)
+	return g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) CreateUniquenessConstraint(label string, propertyKey string) (*SchemaConstraint, *NeoResponse) {
+	result, reqData := g.builder.CreateUniquenessConstraint(label, propertyKey)
+	return result, alreadyExistsResponse(g.executeFromRequestData(reqData), ErrConstraintAlreadyExists)
+}
+
+// Creates the constraint, or returns the existing one (see EnsureSchemaIndex).
+func (g *GraphDatabaseService) EnsureUniquenessConstraint(label string, propertyKey string) (*SchemaConstraint, *NeoResponse) {
+	result, resp := g.CreateUniquenessConstraint(label, propertyKey)
+	if !isConflict(resp, ErrConstraintAlreadyExists) {
+		return result, resp
+	}
+	constraints, getResp := g.GetUniquenessConstraints(label)
+	if getResp.Ok() {
+		for _, constraint := range *constraints {
+			if len(constraint.PropertyKeys) == 1 && constraint.PropertyKeys[0] == propertyKey {
+				return constraint, getResp
+			}
+		}
+	}
+	return result, resp
+}
+
+func (g *GraphDatabaseService) GetUniquenessConstraints(label string) (*[]*SchemaConstraint, *NeoResponse) {
+	result, reqData := g.builder.GetUniquenessConstraints(label)
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) GetConstraints(label string) (*[]*SchemaConstraint, *NeoResponse) {
+	result, reqData := g.builder.GetConstraints(label)
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) GetAllConstraints() (*[]*SchemaConstraint, *NeoResponse) {
+	result, reqData := g.builder.GetAllConstraints()
+	return result, g.executeFromRequestData(reqData)
+}
+
+func (g *GraphDatabaseService) DropUniquenessConstraint(label string, propertyKey string) *NeoResponse {
+	reqData := g.builder.DropUniquenessConstraint(label, propertyKey)
+	return g.executeFromRequestData(reqData)
+}
+
+// The server reports an existing index or constraint with the 409 (Conflict) status. The error is
+// mapped to alreadyExists only if its status code says so, because other conflicts (e.g. an index
+// owned by a constraint) use the same status.
+func alreadyExistsResponse(resp *NeoResponse, alreadyExists *NeoError) *NeoResponse {
+	if errors.Is(resp.Err, alreadyExists) {
+		resp.Err = fmt.Errorf("%w (%w)", alreadyExists, resp.Err)
+	}
+	return resp
+}
+
+// Older servers report the conflicts without a status code, so every conflict has to be checked.
+func isConflict(resp *NeoResponse, alreadyExists *NeoError) bool {
+	return resp.StatusCode == http.StatusConflict || errors.Is(resp.Err, alreadyExists)
+}
+
 // GraphPathFinder
 
 func (g *GraphDatabaseService) FindPathFromNode(start *NeoNode, target *NeoNode, spec *NeoPathFinderSpec) (*NeoPath, *NeoResponse) {
@@ -602,6 +886,9 @@ func (g *GraphDatabaseService) FindPathsFromNode(start *NeoNode, target *NeoNode
 // Utility methods
 
 func (g *GraphDatabaseService) httpRequestFromData(reqData *neoRequestData) (*NeoHttpRequest, error) {
+	if reqData.err != nil {
+		return nil, reqData.err
+	}
 	var bodyBuffer *bytes.Buffer = nil
 
 	if reqData.body != nil {
@@ -610,10 +897,14 @@ func (g *GraphDatabaseService) httpRequestFromData(reqData *neoRequestData) (*Ne
 			return nil, err
 		}
 		bodyBuffer = bytes.NewBuffer(bodyData)
-		return NewNeoHttpRequest(reqData.method, reqData.requestUrl, bodyBuffer)
 	}
 
-	return NewNeoHttpRequest(reqData.method, reqData.requestUrl, nil)
+	req, err := NewNeoHttpRequest(reqData.method, reqData.requestUrl, bodyBuffer)
+	if err != nil {
+		return nil, err
+	}
+	req.retrySafe = reqData.retrySafe
+	return req, nil
 }
 
 func (g *GraphDatabaseService) executeFromRequestData(reqData *neoRequestData) *NeoResponse {
@@ -630,32 +921,102 @@ func (g *GraphDatabaseService) execute(neoRequest *NeoHttpRequest, neoRequestErr
 // If the returned NeoResponse.StatuCode contains a 6xx, it means there was a local error
 // while processing the request or response.
 func (g *GraphDatabaseService) execute_(neoRequest *NeoHttpRequest, neoRequestErr error, expectedStatusCode int, result interface{}, connRequired bool) *NeoResponse {
+	return g.executeWithRetry(neoRequest, neoRequestErr, expectedStatusCode, connRequired, func() *NeoResponse {
+		return g.executeAttempt(neoRequest, expectedStatusCode, result)
+	})
+}
+
+// Calls the attempt function, and calls it again according to the retry policy.
+func (g *GraphDatabaseService) executeWithRetry(neoRequest *NeoHttpRequest, neoRequestErr error, expectedStatusCode int, connRequired bool, attemptFunc func() *NeoResponse) *NeoResponse {
 	if connRequired && (g.builder.root.Data == nil || g.builder.dataRoot.Neo4jVersion == "") {
-		return NewLocalErrorResponse(expectedStatusCode, fmt.Errorf("Cannot execute the request because the client is not connected."))
+		return NewLocalErrorResponse(expectedStatusCode, ErrNotConnected)
 	}
 
 	if neoRequestErr != nil {
 		return NewLocalErrorResponse(expectedStatusCode, neoRequestErr)
 	}
 
+	for attempt := 1; ; attempt++ {
+		neoResponse := attemptFunc()
+		if !g.retryPolicy.shouldRetryRequest(attempt, neoRequest, neoResponse) {
+			return neoResponse
+		}
+		if err := g.retryPolicy.wait(g.Context(), attempt); err != nil {
+			return NewLocalErrorResponse(expectedStatusCode, err)
+		}
+		if err := neoRequest.rewind(); err != nil {
+			return NewLocalErrorResponse(expectedStatusCode, err)
+		}
+	}
+}
+
+func (g *GraphDatabaseService) executeAttempt(neoRequest *NeoHttpRequest, expectedStatusCode int, result interface{}) *NeoResponse {
+	resp, cancel, neoResponse := g.roundTrip(neoRequest, expectedStatusCode)
+	if resp == nil {
+		return neoResponse
+	}
+	defer cancel()
+	defer resp.Body.Close()
+	return decodeResponse(resp, neoResponse, result)
+}
+
+// Sends the request and returns the HTTP response, without reading its body.
+// If the request fails, the returned http.Response is nil and the NeoResponse contains the error.
+// Otherwise, the returned function must be called after the response body is read and closed.
+func (g *GraphDatabaseService) roundTrip(neoRequest *NeoHttpRequest, expectedStatusCode int) (*http.Response, context.CancelFunc, *NeoResponse) {
+	for key, values := range g.defaultHeaders {
+		neoRequest.Request.Header[key] = append([]string(nil), values...)
+	}
 	if g.basicAuthPayload != "" {
 		neoRequest.Request.Header.Set("Authorization", "Basic "+g.basicAuthPayload)
 	}
 
-	g.maxConnChannel <- 1
-	resp, err := g.client.Do(neoRequest.Request)
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if g.requestTimeout > 0 {
+		ctx, cancel = context.WithTimeout(g.Context(), g.requestTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(g.Context())
+	}
+	select {
+	case g.maxConnChannel <- 1:
+	case <-ctx.Done():
+		cancel()
+		return nil, nil, NewLocalErrorResponse(expectedStatusCode, ctx.Err())
+	}
+	resp, err := g.client.Do(neoRequest.Request.WithContext(ctx))
 	_ = <-g.maxConnChannel
 	if err != nil {
-		return NewLocalErrorResponse(expectedStatusCode, err)
+		cancel()
+		return nil, nil, NewLocalErrorResponse(expectedStatusCode, err)
 	}
 
-	defer resp.Body.Close()
-	var container interface{}
-
 	neoResponse := new(NeoResponse)
 	neoResponse.ExpectedCode = expectedStatusCode
 	neoResponse.StatusCode = resp.StatusCode
 
+	locationUrl, err := resp.Location()
+	if err == nil {
+		neoResponse.location = locationUrl.String()
+	}
+
+	return resp, cancel, neoResponse
+}
+
+func checkJsonContentType(resp *http.Response) error {
+	ctype := resp.Header.Get("content-type")
+	if len(ctype) == 0 {
+		return ErrMissingContentType
+	} else if !jsonContentTypeRegExp.MatchString(ctype) {
+		return fmt.Errorf("%w (%s)", ErrUnsupportedContentType, ctype)
+	}
+	return nil
+}
+
+// Decodes the response body into result, or into NeoErrors if the request has failed.
+func decodeResponse(resp *http.Response, neoResponse *NeoResponse, result interface{}) *NeoResponse {
+	var container interface{}
+
 	if resp.StatusCode >= 400 {
 		neoErr := &NeoErrors{Errors: make([]NeoError, 0)}
 		container = neoErr
@@ -664,30 +1025,20 @@ func (g *GraphDatabaseService) execute_(neoRequest *NeoHttpRequest, neoRequestEr
 		container = result
 	}
 
-	locationUrl, err := resp.Location()
-	if err == nil {
-		neoResponse.location = locationUrl.String()
-	}
-
 	if container != nil {
 		if selfAware, ok := container.(selfUrlAware); ok && neoResponse.location != "" {
 			selfAware.SetSelf(NewUrlTemplate(neoResponse.location))
 		}
 
-		ctype := resp.Header.Get("content-type")
-		matched := jsonContentTypeRegExp.MatchString(ctype)
-
-		if matched {
-			dec := json.NewDecoder(resp.Body)
-			err = dec.Decode(container)
-			if err != nil {
-				return NewLocalErrorResponse(expectedStatusCode, err)
-			}
-		} else if len(ctype) == 0 {
-			return NewLocalErrorResponse(expectedStatusCode, fmt.Errorf("Server did not return a content-type for this response."))
-		} else {
-			err := fmt.Errorf("Server has returned a response with unsupported content-type (%s)", ctype)
-			return NewLocalErrorResponse(expectedStatusCode, err)
+		if err := checkJsonContentType(resp); err != nil {
+			return NewLocalErrorResponse(neoResponse.ExpectedCode, err)
+		}
+		dec := json.NewDecoder(resp.Body)
+		if err := dec.Decode(container); err != nil {
+			return NewLocalErrorResponse(neoResponse.ExpectedCode, err)
+		}
+		if reporter, ok := container.(resultErrorReporter); ok && neoResponse.Err == nil {
+			neoResponse.Err = reporter.resultError()
 		}
 	}
 
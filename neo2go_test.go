@@ -1,9 +1,19 @@
 package neo2go
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -40,6 +50,25 @@ func getDefaultDb() *GraphDatabaseService {
 	return defaultNeoService
 }
 
+// Starts a local HTTP server which answers the discovery requests made by Connect,
+// and passes every other request to the given handler.
+func newFakeNeoServer(handler http.HandlerFunc) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"data":"%s/db/data/"}`, server.URL)
+		case "/db/data/":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"node":"%[1]s/db/data/node","batch":"%[1]s/db/data/batch","cypher":"%[1]s/db/data/cypher","transaction":"%[1]s/db/data/transaction","node_labels":"%[1]s/db/data/labels","node_index":"%[1]s/db/data/index/node","relationship_index":"%[1]s/db/data/index/relationship","indexes":"%[1]s/db/data/schema/index","constraints":"%[1]s/db/data/schema/constraint","neo4j_version":"2.2.0"}`, server.URL)
+		default:
+			handler(w, r)
+		}
+	}))
+	return server
+}
+
 func TestConnecting(t *testing.T) {
 	service := getDefaultDb()
 	resp := service.Connect(databaseAddress)
@@ -1155,3 +1184,794 @@ func TestPagedTraverseByNodes(t *testing.T) {
 	resp = service.DeleteNode(target)
 	checkResponseSucceeded(t, resp, 204)
 }
+
+func TestRequestCancelledByContext(t *testing.T) {
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		<-r.Context().Done()
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseServiceWithMaxConn(1)
+	resp := service.Connect(server.URL)
+	checkResponseSucceeded(t, resp, 200)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, resp = service.WithContext(ctx).CreateNode()
+	if !responseHasFailedWithCode(resp, 600) {
+		t.Fatalf("Expected the request to be cancelled, but got %d: %v", resp.StatusCode, resp.Err)
+	}
+
+	// The connection slot must have been released, so a subsequent request is not blocked.
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel2()
+	batch := service.Batch()
+	batch.CreateNode()
+	resp = batch.CommitWithContext(ctx2)
+	if !responseHasFailedWithCode(resp, 600) {
+		t.Fatalf("Expected the batch to be cancelled, but got %d: %v", resp.StatusCode, resp.Err)
+	}
+}
+
+func TestWaitingForConnectionSlotCancelledByContext(t *testing.T) {
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {})
+	defer server.Close()
+
+	service := NewGraphDatabaseServiceWithMaxConn(1)
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	service.maxConnChannel <- 1
+	defer func() { <-service.maxConnChannel }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, resp := service.WithContext(ctx).CreateNode()
+	if resp.Err != context.Canceled {
+		t.Fatalf("Expected the request to fail with %v, but got: %v", context.Canceled, resp.Err)
+	}
+}
+
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.requests = append(r.requests, req)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestServiceOptions(t *testing.T) {
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	})
+	defer server.Close()
+
+	transport := new(recordingTransport)
+	service := NewGraphDatabaseServiceWithOptions(
+		WithHttpTransport(transport),
+		WithDefaultHeader("X-Request-Source", "tests"),
+		WithBasicAuth("user", "secret"),
+		WithRequestTimeout(20*time.Millisecond),
+	)
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	if len(transport.requests) != 2 {
+		t.Fatalf("Expected the custom transport to be used for 2 requests, but got %d", len(transport.requests))
+	}
+	for _, req := range transport.requests {
+		if val := req.Header.Get("X-Request-Source"); val != "tests" {
+			t.Fatalf("Expected the default header to be sent, but got %q", val)
+		}
+		if user, pass, ok := req.BasicAuth(); !ok || user != "user" || pass != "secret" {
+			t.Fatalf("Expected basic auth credentials to be sent.")
+		}
+	}
+
+	_, resp := service.CreateNode()
+	if !responseHasFailedWithCode(resp, 600) {
+		t.Fatalf("Expected the request to time out, but got %d: %v", resp.StatusCode, resp.Err)
+	}
+}
+
+func TestRetryingTransientErrors(t *testing.T) {
+	attempts := 0
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		if attempts < 3 {
+			w.WriteHeader(500)
+			fmt.Fprint(w, `{"errors":[{"code":"Neo.TransientError.Transaction.DeadlockDetected","message":"deadlock"}]}`)
+			return
+		}
+		if r.URL.Path == "/db/data/batch" {
+			fmt.Fprint(w, `[{"id":1,"status":201}]`)
+			return
+		}
+		fmt.Fprintf(w, `{"self":"http://%s/db/data/node/1"}`, r.Host)
+	})
+	defer server.Close()
+
+	policy := NewRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	service := NewGraphDatabaseServiceWithOptions(WithRetryPolicy(policy))
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	node, resp := service.GetNode(server.URL + "/db/data/node/1")
+	checkResponseSucceeded(t, resp, 200)
+	if attempts != 3 || node.Id() != 1 {
+		t.Fatalf("Expected the node to be fetched after 3 attempts, but got %d attempts (node: %v)", attempts, node)
+	}
+
+	// Creating a node is not idempotent, so it should not be retried.
+	attempts = 0
+	_, resp = service.CreateNode()
+	if !responseHasFailedWithCode(resp, 500) || !resp.IsTransient() || attempts != 1 {
+		t.Fatalf("Expected a single failed attempt, but got %d attempts (%d: %v)", attempts, resp.StatusCode, resp.Err)
+	}
+
+	// A batch is executed in one transaction, so it can be retried.
+	attempts = 1
+	batch := service.Batch()
+	batch.CreateNode()
+	resp = batch.Commit()
+	if resp.StatusCode != 200 || attempts != 3 {
+		t.Fatalf("Expected the batch to be sent twice, but got %d attempts (%d: %v)", attempts-1, resp.StatusCode, resp.Err)
+	}
+}
+
+func TestRetryingConnectionRefused(t *testing.T) {
+	policy := NewRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	transport := new(recordingTransport)
+	service := NewGraphDatabaseServiceWithOptions(WithRetryPolicy(policy), WithHttpTransport(transport))
+
+	resp := service.Connect(databaseAddressWithInvalidPort)
+	if resp.Err == nil {
+		t.Fatalf("Connection succeeded, but should not.")
+	}
+	if len(transport.requests) != policy.MaxAttempts {
+		t.Fatalf("Expected %d attempts, but got %d", policy.MaxAttempts, len(transport.requests))
+	}
+}
+
+func TestManagedTransaction(t *testing.T) {
+	var calls []string
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/db/data/transaction" {
+			w.Header().Set("Location", "http://"+r.Host+"/db/data/transaction/7")
+			w.WriteHeader(201)
+		}
+		fmt.Fprintf(w, `{"commit":"http://%s/db/data/transaction/7/commit","results":[],"transaction":{"expires":"Tue, 22 Dec 2015 11:13:03 +0000"},"errors":[]}`, r.Host)
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	resp := service.WithTransaction(func(tx *ManagedCypherTransaction) error {
+		expires, err := tx.Expires()
+		if err != nil || expires.Year() != 2015 {
+			t.Fatalf("Unexpected transaction expiration time %v: %v", expires, err)
+		}
+		_, resp := tx.Execute(&CypherTransactionRequest{Cql: "CREATE (n)"})
+		if !resp.Ok() {
+			return resp.Err
+		}
+		return tx.ResetTimeout().Err
+	})
+	checkResponseSucceeded(t, resp, 200)
+
+	expected := "POST /db/data/transaction,POST /db/data/transaction/7,POST /db/data/transaction/7,POST /db/data/transaction/7/commit"
+	if actual := strings.Join(calls, ","); actual != expected {
+		t.Fatalf("Expected requests %v, but got %v", expected, actual)
+	}
+
+	calls = nil
+	workErr := fmt.Errorf("failure")
+	resp = service.WithTransaction(func(tx *ManagedCypherTransaction) error {
+		return workErr
+	})
+	if resp.Ok() || resp.Err != workErr {
+		t.Fatalf("Expected the transaction to fail with %v, but got: %v", workErr, resp.Err)
+	}
+	if last := calls[len(calls)-1]; last != "DELETE /db/data/transaction/7" {
+		t.Fatalf("Expected the transaction to be rolled back, but the last request was: %v", last)
+	}
+
+	calls = nil
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("Expected the panic to be propagated.")
+			}
+		}()
+		service.WithTransaction(func(tx *ManagedCypherTransaction) error {
+			panic("failure")
+		})
+	}()
+	if last := calls[len(calls)-1]; last != "DELETE /db/data/transaction/7" {
+		t.Fatalf("Expected the transaction to be rolled back, but the last request was: %v", last)
+	}
+}
+
+func TestCypherStream(t *testing.T) {
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/db/data/cypher":
+			fmt.Fprint(w, `{"columns":["name","age"],"data":[["Alice",31],["Bob",42]]}`)
+		case "/db/data/transaction/commit":
+			fmt.Fprint(w, `{"results":[{"columns":["n"],"data":[{"rest":[1]}]},{"columns":["m"],"data":[{"rest":[2]},{"rest":[3]}]}],`+
+				`"errors":[{"code":"Neo.ClientError.Statement.InvalidSyntax","message":"failure"}]}`)
+		}
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	stream, resp := service.CypherStream("MATCH (p) RETURN p.name AS name, p.age AS age", nil)
+	checkResponseSucceeded(t, resp, 200)
+	var people []string
+	for stream.Next() {
+		var person struct {
+			Name string
+			Age  int
+		}
+		if err := stream.Scan(&person); err != nil {
+			t.Fatal(err)
+		}
+		people = append(people, fmt.Sprintf("%s:%d", person.Name, person.Age))
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if actual := strings.Join(people, ","); actual != "Alice:31,Bob:42" {
+		t.Fatalf("Unexpected rows: %v", actual)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Closing a finished stream should succeed, but got: %v", err)
+	}
+
+	stream, resp = service.CypherAutoCommitStream(&CypherTransactionRequest{Cql: "RETURN 1"}, &CypherTransactionRequest{Cql: "RETURN 2"})
+	checkResponseSucceeded(t, resp, 200)
+	var values []string
+	for stream.Next() {
+		var value int
+		if err := stream.Scan(&value); err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, fmt.Sprintf("%d/%s=%d", stream.ResultIndex(), stream.Columns()[0], value))
+	}
+	if actual := strings.Join(values, ","); actual != "0/n=1,1/m=2,1/m=3" {
+		t.Fatalf("Unexpected rows: %v", actual)
+	}
+	if err := stream.Err(); err == nil {
+		t.Fatalf("Expected the errors reported by the server.")
+	}
+}
+
+func TestExecuteCypherStream(t *testing.T) {
+	var calls []string
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/db/data/transaction":
+			w.Header().Set("Location", "http://"+r.Host+"/db/data/transaction/7")
+			w.WriteHeader(201)
+			fmt.Fprintf(w, `{"commit":"http://%s/db/data/transaction/7/commit","results":[],`+
+				`"transaction":{"expires":"Fri, 17 Oct 2014 10:00:00 +0000"},"errors":[]}`, r.Host)
+		case "/db/data/transaction/7":
+			fmt.Fprintf(w, `{"commit":"http://%s/db/data/transaction/7/commit","results":[{"columns":["n"],"data":[{"rest":[1]},{"rest":[2]}]}],`+
+				`"transaction":{"expires":"Fri, 17 Oct 2014 10:01:00 +0000"},"errors":[]}`, r.Host)
+		case "/db/data/transaction/7/commit":
+			fmt.Fprint(w, `{"results":[],"errors":[]}`)
+		}
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	trans, resp := service.NewCypherTransaction()
+	checkResponseSucceeded(t, resp, 201)
+	stream, resp := service.ExecuteCypherStream(trans, &CypherTransactionRequest{Cql: "UNWIND [1, 2] AS n RETURN n"})
+	checkResponseSucceeded(t, resp, 200)
+	var values []int
+	for stream.Next() {
+		var value int
+		if err := stream.Scan(&value); err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, value)
+	}
+	if err := stream.Err(); err != nil || len(values) != 2 || values[1] != 2 {
+		t.Fatalf("Unexpected rows: %v (%v)", values, err)
+	}
+
+	streamed := stream.Transaction()
+	if streamed == nil || streamed.Self.String() != trans.Self.String() || streamed.Commit.String() != trans.Commit.String() {
+		t.Fatalf("Unexpected transaction: %+v", streamed)
+	}
+	if expires, err := streamed.Expires(); err != nil || expires.Minute() != 1 {
+		t.Fatalf("Expected the new expiration time, but got: %v (%v)", expires, err)
+	}
+	_, resp = service.CommitCypher(streamed)
+	checkResponseSucceeded(t, resp, 200)
+
+	expected := "POST /db/data/transaction,POST /db/data/transaction/7,POST /db/data/transaction/7/commit"
+	if actual := strings.Join(calls, ","); actual != expected {
+		t.Fatalf("Expected requests %v, but got %v", expected, actual)
+	}
+}
+
+func TestCypherResultDataContents(t *testing.T) {
+	var statements struct {
+		Statements []map[string]interface{} `json:"statements"`
+	}
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&statements)
+		w.Header().Set("Content-Type", "application/json")
+		node := func(id int) string {
+			return fmt.Sprintf(`{"id":"%d","labels":["Person"],"properties":{"id":%d}}`, id, id)
+		}
+		fmt.Fprintf(w, `{"results":[{"columns":["a","b"],"data":[`+
+			`{"row":[{"id":1},{"id":2}],"graph":{"nodes":[%s,%s],"relationships":[{"id":"5","type":"KNOWS","startNode":"1","endNode":"2","properties":{}}]}},`+
+			`{"row":[{"id":1},{"id":3}],"graph":{"nodes":[%s,%s],"relationships":[]}}]}],"errors":[]}`, node(1), node(2), node(1), node(3))
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	result, resp := service.CypherAutoCommit(&CypherTransactionRequest{
+		Cql:                "MATCH (a)-[r]-(b) RETURN a, b",
+		ResultDataContents: []CypherResultDataContent{CypherRowContent, CypherGraphContent},
+		IncludeStats:       true,
+	}, &CypherTransactionRequest{Cql: "RETURN 1"})
+	checkResponseSucceeded(t, resp, 200)
+
+	first, second := statements.Statements[0], statements.Statements[1]
+	if fmt.Sprint(first["resultDataContents"]) != "[row graph]" || first["includeStats"] != true {
+		t.Fatalf("Unexpected statement: %v", first)
+	}
+	if fmt.Sprint(second["resultDataContents"]) != "[REST]" || second["includeStats"] != nil {
+		t.Fatalf("Unexpected statement: %v", second)
+	}
+
+	graph := result.Results[0].Graph()
+	if len(graph.Nodes) != 3 || len(graph.Relationships) != 1 {
+		t.Fatalf("Expected 3 nodes and 1 relationship, but got: %d, %d", len(graph.Nodes), len(graph.Relationships))
+	}
+	if rel := graph.Relationships[0]; rel.Type != "KNOWS" || rel.StartNode != "1" || rel.EndNode != "2" {
+		t.Fatalf("Unexpected relationship: %v", rel)
+	}
+
+	var ids []struct {
+		A struct{ Id int }
+		B struct{ Id int }
+	}
+	if err := result.Results[0].ScanAll(&ids); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[1].A.Id != 1 || ids[1].B.Id != 3 {
+		t.Fatalf("Unexpected rows: %v", ids)
+	}
+}
+
+func TestCypherStatsAndNotifications(t *testing.T) {
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results":[{"columns":[],"data":[],"stats":{"contains_updates":true,"nodes_created":2,"relationship_deleted":1}}],`+
+			`"notifications":[{"code":"Neo.ClientNotification.Statement.CartesianProduct","severity":"WARNING","title":"Cartesian product",`+
+			`"position":{"offset":0,"line":1,"column":1}}],"errors":[]}`)
+	})
+	defer server.Close()
+
+	var logged bytes.Buffer
+	service := NewGraphDatabaseServiceWithOptions(WithNotificationHandler(LogNotifications(log.New(&logged, "", 0))))
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	result, resp := service.CypherAutoCommit(&CypherTransactionRequest{Cql: "MATCH (a), (b) CREATE (a)-[:R]->(b)", IncludeStats: true})
+	checkResponseSucceeded(t, resp, 200)
+	stats := result.Results[0].Stats
+	if stats == nil || !stats.ContainsUpdates || stats.NodesCreated != 2 || stats.RelationshipsDeleted != 1 {
+		t.Fatalf("Unexpected stats: %v", stats)
+	}
+	if len(result.Notifications) != 1 || result.Notifications[0].Code != Neo_ClientNotification_Statement_CartesianProduct {
+		t.Fatalf("Unexpected notifications: %v", result.Notifications)
+	}
+	if !strings.Contains(logged.String(), Neo_ClientNotification_Statement_CartesianProduct) {
+		t.Fatalf("Expected the notification to be logged, but got: %v", logged.String())
+	}
+
+	service = NewGraphDatabaseServiceWithOptions(WithNotificationHandler(FailOnNotifications(Neo_ClientNotification_Statement_DeprecationWarning)))
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+	_, resp = service.CypherAutoCommit(&CypherTransactionRequest{Cql: "MATCH (a), (b) RETURN a, b"})
+	checkResponseSucceeded(t, resp, 200)
+
+	service = NewGraphDatabaseServiceWithOptions(WithNotificationHandler(FailOnNotifications(Neo_ClientNotification_Statement_CartesianProduct)))
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+	_, resp = service.CypherAutoCommit(&CypherTransactionRequest{Cql: "MATCH (a), (b) RETURN a, b"})
+	if resp.Ok() || resp.Err == nil {
+		t.Fatalf("Expected the request to fail because of the notification.")
+	}
+}
+
+func TestRejectedNotificationsRollBackTransaction(t *testing.T) {
+	var calls []string
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "DELETE" {
+			fmt.Fprint(w, `{"results":[],"errors":[]}`)
+			return
+		}
+		if r.URL.Path == "/db/data/transaction" {
+			w.Header().Set("Location", "http://"+r.Host+"/db/data/transaction/7")
+			w.WriteHeader(201)
+		}
+		fmt.Fprintf(w, `{"commit":"http://%s/db/data/transaction/7/commit","results":[{"columns":[],"data":[]}],`+
+			`"notifications":[{"code":"Neo.ClientNotification.Statement.CartesianProduct","severity":"WARNING","title":"Cartesian product"}],"errors":[]}`, r.Host)
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseServiceWithOptions(WithNotificationHandler(FailOnNotifications(Neo_ClientNotification_Statement_CartesianProduct)))
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	_, resp := service.NewCypherTransaction(&CypherTransactionRequest{Cql: "MATCH (a), (b) RETURN a, b"})
+	if resp.Ok() || resp.Err == nil {
+		t.Fatalf("Expected the request to fail because of the notification.")
+	}
+	expected := "POST /db/data/transaction,DELETE /db/data/transaction/7"
+	if actual := strings.Join(calls, ","); actual != expected {
+		t.Fatalf("Expected requests %v, but got %v", expected, actual)
+	}
+}
+
+func TestProfileCypher(t *testing.T) {
+	var statement string
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Statements []struct{ Statement string } `json:"statements"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		statement = body.Statements[0].Statement
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results":[{"columns":["n"],"data":[],"plan":{"root":{"operatorType":"ProduceResults","identifiers":["n"],`+
+			`"EstimatedRows":10.0,"DbHits":0,"Rows":10,"version":"CYPHER 2.2","children":[{"operatorType":"NodeByLabelScan",`+
+			`"identifiers":["n"],"EstimatedRows":10.0,"DbHits":11,"Rows":10,"LabelName":":Person","children":[]}]}}}],"errors":[]}`)
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	result, resp := service.ProfileCypher("MATCH (n:Person) RETURN n", nil)
+	checkResponseSucceeded(t, resp, 200)
+	if statement != "PROFILE MATCH (n:Person) RETURN n" {
+		t.Fatalf("Unexpected statement: %v", statement)
+	}
+
+	root := result.Plan.Root
+	if root.OperatorType != "ProduceResults" || root.Rows != 10 || len(root.Children) != 1 || root.Arguments["version"] != "CYPHER 2.2" {
+		t.Fatalf("Unexpected plan: %#v", root)
+	}
+	if child := root.Children[0]; child.OperatorType != "NodeByLabelScan" || child.DbHits != 11 || child.EstimatedRows != 10 {
+		t.Fatalf("Unexpected plan: %#v", child)
+	}
+
+	expected := "+ ProduceResults [n] (estimated rows: 10, rows: 10, db hits: 0) version=CYPHER 2.2\n" +
+		"  + NodeByLabelScan [n] (estimated rows: 10, rows: 10, db hits: 11) LabelName=:Person\n"
+	if actual := result.Plan.String(); actual != expected {
+		t.Fatalf("Expected plan:\n%v\nbut got:\n%v", expected, actual)
+	}
+
+	var legacy CypherPlan
+	if err := json.Unmarshal([]byte(`{"name":"ColumnFilter","args":{"returnItemNames":["n"],"rows":1,"dbHits":0},"children":[]}`), &legacy); err != nil {
+		t.Fatal(err)
+	}
+	if legacy.Root.OperatorType != "ColumnFilter" || legacy.Root.Rows != 1 || legacy.Root.Arguments["returnItemNames"] == nil {
+		t.Fatalf("Unexpected legacy plan: %#v", legacy.Root)
+	}
+}
+
+func TestProfileLegacyCypher(t *testing.T) {
+	var requests []string
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, r.URL.RequestURI()+" "+body.Query)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"columns":["n.name"],"data":[["Jon"]],"plan":{"name":"ColumnFilter",`+
+			`"args":{"symKeys":["n","n.name"],"returnItemNames":["n.name"],"_rows":1,"_db_hits":0},`+
+			`"children":[{"name":"Extract","args":{"symKeys":["n"],"exprKeys":["n.name"],"_rows":1,"_db_hits":1},`+
+			`"children":[{"name":"NodeByLabel","args":{"identifier":"n","_db_hits":0,"_rows":1,"label":"Person","identifiers":["n"],"producer":"NodeByLabel"},`+
+			`"children":[]}]}]}}`)
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	result, resp := service.ProfileLegacyCypher("MATCH (n:Person) RETURN n.name", nil)
+	checkResponseSucceeded(t, resp, 200)
+	if len(result.Data) != 1 || string(result.Data[0][0]) != `"Jon"` {
+		t.Fatalf("Unexpected data: %v", result.Data)
+	}
+	root := result.Plan.Root
+	if root.OperatorType != "ColumnFilter" || root.Rows != 1 || len(root.Children) != 1 || root.Arguments["_rows"] != nil {
+		t.Fatalf("Unexpected plan: %#v", root)
+	}
+	if child := root.Children[0]; child.OperatorType != "Extract" || child.DbHits != 1 || len(child.Children) != 1 {
+		t.Fatalf("Unexpected plan: %#v", child)
+	}
+
+	_, resp = service.ExplainLegacyCypher("MATCH (n:Person) RETURN n.name", nil)
+	checkResponseSucceeded(t, resp, 200)
+
+	expected := "/db/data/cypher?profile=true MATCH (n:Person) RETURN n.name,/db/data/cypher EXPLAIN MATCH (n:Person) RETURN n.name"
+	if actual := strings.Join(requests, ","); actual != expected {
+		t.Fatalf("Expected requests %v, but got %v", expected, actual)
+	}
+}
+
+func TestCypherTransactionErrors(t *testing.T) {
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"results":[{"columns":["n"],"data":[{"rest":[1]}]}],`+
+			`"errors":[{"code":"Neo.ClientError.Statement.InvalidSyntax","message":"Invalid input"}]}`)
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	result, resp := service.CypherAutoCommit(&CypherTransactionRequest{Cql: "RETURN 1"}, &CypherTransactionRequest{Cql: "RETURN"})
+	if resp.Ok() || resp.StatusCode != 200 {
+		t.Fatalf("Expected the request to fail, but got: %d (%v)", resp.StatusCode, resp.Err)
+	}
+	transErr, ok := resp.Err.(*CypherTransactionError)
+	if !ok || len(transErr.Errors) != 1 {
+		t.Fatalf("Unexpected error: %#v", resp.Err)
+	}
+	if stmtErr := transErr.Errors[0]; stmtErr.StatementIndex != 1 || stmtErr.Code != Neo_ClientError_Statement_InvalidSyntax {
+		t.Fatalf("Unexpected statement error: %#v", stmtErr)
+	}
+	if len(result.Results) != 1 || len(result.Errors) != 1 {
+		t.Fatalf("Expected the partial results and the errors, but got: %#v", result)
+	}
+}
+
+func TestNodeLabels(t *testing.T) {
+	var requests []string
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, strings.TrimSpace(r.Method+" "+r.URL.RequestURI()+" "+string(body)))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "PUT" || r.Method == "DELETE":
+			w.WriteHeader(204)
+		case strings.HasSuffix(r.URL.Path, "/nodes"):
+			fmt.Fprintf(w, `[{"self":"http://%s/db/data/node/3","data":{"name":"Clint Eastwood"}}]`, r.Host)
+		default:
+			fmt.Fprint(w, `["Person","Actor"]`)
+		}
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+	node := &NeoNode{Labels: NewUrlTemplate(server.URL + "/db/data/node/3/labels")}
+
+	labels, resp := service.GetLabelsForNode(node)
+	checkResponseSucceeded(t, resp, 200)
+	if len(*labels) != 2 || (*labels)[1] != "Actor" {
+		t.Fatalf("Unexpected labels: %v", *labels)
+	}
+	_, resp = service.GetAllLabels()
+	checkResponseSucceeded(t, resp, 200)
+	checkResponseSucceeded(t, service.ReplaceLabels(node, []string{"Director"}), 204)
+	checkResponseSucceeded(t, service.RemoveLabel(node, "Old Actor"), 204)
+	_, resp = service.GetNodesWithLabel("Person")
+	checkResponseSucceeded(t, resp, 200)
+	nodes, resp := service.GetNodesWithLabelAndProperty("Person", "name", "Clint Eastwood")
+	checkResponseSucceeded(t, resp, 200)
+	if len(*nodes) != 1 || (*nodes)[0].Id() != 3 {
+		t.Fatalf("Unexpected nodes: %v", *nodes)
+	}
+
+	expected := []string{
+		"GET /db/data/node/3/labels",
+		"GET /db/data/labels",
+		`PUT /db/data/node/3/labels ["Director"]`,
+		"DELETE /db/data/node/3/labels/Old%20Actor",
+		"GET /db/data/label/Person/nodes",
+		"GET /db/data/label/Person/nodes?name=%22Clint+Eastwood%22",
+	}
+	if actual := strings.Join(requests, "\n"); actual != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected requests:\n%v", actual)
+	}
+}
+
+func TestSchemaManager(t *testing.T) {
+	var requests []string
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && strings.Contains(r.URL.Path, "/constraint/"):
+			w.WriteHeader(409)
+			fmt.Fprint(w, `{"message":"Already exists","exception":"AlreadyConstrainedException","errors":[]}`)
+		case r.Method == "POST":
+			w.WriteHeader(409)
+			fmt.Fprint(w, `{"message":"Already exists","exception":"AlreadyIndexedException","errors":[{"code":"Neo.ClientError.Schema.IndexAlreadyExists","message":"Already exists"}]}`)
+		case r.Method == "DELETE":
+			w.WriteHeader(204)
+		case strings.Contains(r.URL.Path, "/constraint/"):
+			fmt.Fprint(w, `[{"label":"Person","type":"UNIQUENESS","property_keys":["email"]}]`)
+		default:
+			fmt.Fprint(w, `[{"label":"Person","property_keys":["age"]},{"label":"Person","property_keys":["name"]}]`)
+		}
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	_, resp := service.CreateSchemaIndex("Person", "name")
+	if !errors.Is(resp.Err, ErrIndexAlreadyExists) || errors.Is(resp.Err, ErrConstraintAlreadyExists) {
+		t.Fatalf("Expected %v, but got: %v", ErrIndexAlreadyExists, resp.Err)
+	}
+	var neoErrors *NeoErrors
+	if !errors.As(resp.Err, &neoErrors) || neoErrors.Exception != "AlreadyIndexedException" {
+		t.Fatalf("Expected the error reported by the server, but got: %v", resp.Err)
+	}
+
+	index, resp := service.EnsureSchemaIndex("Person", "name")
+	checkResponseSucceeded(t, resp, 200)
+	if index.Label != "Person" || index.PropertyKeys[0] != "name" {
+		t.Fatalf("Unexpected index: %v", index)
+	}
+	constraint, resp := service.EnsureUniquenessConstraint("Person", "email")
+	checkResponseSucceeded(t, resp, 200)
+	if constraint.Type != SchemaConstraintUniqueness || constraint.PropertyKeys[0] != "email" {
+		t.Fatalf("Unexpected constraint: %v", constraint)
+	}
+	_, resp = service.GetAllConstraints()
+	checkResponseSucceeded(t, resp, 200)
+	checkResponseSucceeded(t, service.DropSchemaIndex("Person", "name"), 204)
+	checkResponseSucceeded(t, service.DropUniquenessConstraint("Person", "email"), 204)
+
+	expected := []string{
+		"POST /db/data/schema/index/Person",
+		"POST /db/data/schema/index/Person",
+		"GET /db/data/schema/index/Person",
+		"POST /db/data/schema/constraint/Person/uniqueness",
+		"GET /db/data/schema/constraint/Person/uniqueness",
+		"GET /db/data/schema/constraint",
+		"DELETE /db/data/schema/index/Person/name",
+		"DELETE /db/data/schema/constraint/Person/uniqueness/email",
+	}
+	if actual := strings.Join(requests, "\n"); actual != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected requests:\n%v", actual)
+	}
+}
+
+func TestEnsureSchemaIndexConflict(t *testing.T) {
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			w.WriteHeader(409)
+			fmt.Fprint(w, `{"message":"Label 'Person' and property 'email' have a unique constraint defined on them.",`+
+				`"errors":[{"code":"Neo.ClientError.Schema.IndexBelongsToConstraint","message":"Owned by a constraint"}]}`)
+			return
+		}
+		fmt.Fprint(w, `[{"label":"Person","property_keys":["age"]}]`)
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	_, resp := service.CreateSchemaIndex("Person", "email")
+	if resp.StatusCode != 409 || errors.Is(resp.Err, ErrIndexAlreadyExists) {
+		t.Fatalf("Expected a conflict, which is not %v, but got: %v", ErrIndexAlreadyExists, resp.Err)
+	}
+
+	index, resp := service.EnsureSchemaIndex("Person", "email")
+	if resp.Ok() || resp.StatusCode != 409 {
+		t.Fatalf("Expected the conflict, when the index is not found, but got: %d (%v)", resp.StatusCode, resp.Err)
+	}
+	if !errors.Is(resp.Err, &NeoError{Code: Neo_ClientError_Schema_IndexBelongsToConstraint}) {
+		t.Fatalf("Expected the error reported by the server, but got: %v", resp.Err)
+	}
+	if index == nil || index.Label != "" {
+		t.Fatalf("Unexpected index: %v", index)
+	}
+}
+
+func TestAutoIndexes(t *testing.T) {
+	var requests []string
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, strings.TrimSpace(r.Method+" "+r.URL.RequestURI()+" "+string(body)))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/db/data/batch":
+			fmt.Fprint(w, `[{"id":1,"status":204},{"id":2,"status":200,"body":true}]`)
+		case r.Method != "GET":
+			w.WriteHeader(204)
+		case strings.HasSuffix(r.URL.Path, "/status"):
+			fmt.Fprint(w, `true`)
+		case strings.HasSuffix(r.URL.Path, "/properties"):
+			fmt.Fprint(w, `["name"]`)
+		default:
+			fmt.Fprintf(w, `[{"self":"http://%s/db/data/relationship/4","type":"KNOWS"}]`, r.Host)
+		}
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	status, resp := service.GetNodeAutoIndexStatus()
+	checkResponseSucceeded(t, resp, 200)
+	if !*status {
+		t.Fatalf("Expected the auto-indexing to be enabled.")
+	}
+	checkResponseSucceeded(t, service.SetRelationshipAutoIndexStatus(false), 204)
+	properties, resp := service.GetNodeAutoIndexProperties()
+	checkResponseSucceeded(t, resp, 200)
+	if len(*properties) != 1 || (*properties)[0] != "name" {
+		t.Fatalf("Unexpected properties: %v", *properties)
+	}
+	checkResponseSucceeded(t, service.AddNodeAutoIndexProperty("name"), 204)
+	checkResponseSucceeded(t, service.DeleteRelationshipAutoIndexProperty("since year"), 204)
+	rels, resp := service.FindRelationshipByExactMatchingAutoIndex("since", "2010")
+	checkResponseSucceeded(t, resp, 200)
+	if len(*rels) != 1 || (*rels)[0].Type != "KNOWS" {
+		t.Fatalf("Unexpected relationships: %v", *rels)
+	}
+	_, resp = service.FindNodeByQueryingAutoIndex("name:Cl*")
+	checkResponseSucceeded(t, resp, 200)
+
+	batch := service.Batch()
+	batch.SetNodeAutoIndexStatus(true)
+	status, _ = batch.GetNodeAutoIndexStatus()
+	checkResponseSucceeded(t, batch.Commit(), 200)
+	if !*status {
+		t.Fatalf("Expected the auto-indexing to be enabled.")
+	}
+
+	expected := []string{
+		"GET /db/data/index/auto/node/status",
+		"PUT /db/data/index/auto/relationship/status false",
+		"GET /db/data/index/auto/node/properties",
+		`POST /db/data/index/auto/node/properties "name"`,
+		"DELETE /db/data/index/auto/relationship/properties/since%20year",
+		"GET /db/data/index/auto/relationship/since/2010",
+		"GET /db/data/index/auto/node/?query=name%3ACl%2A",
+		`POST /db/data/batch [{"body":true,"id":1,"method":"PUT","to":"/index/auto/node/status"},{"body":null,"id":2,"method":"GET","to":"/index/auto/node/status"}]`,
+	}
+	if actual := strings.Join(requests, "\n"); actual != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected requests:\n%v", actual)
+	}
+}
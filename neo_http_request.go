@@ -14,6 +14,9 @@ func init() {
 
 type NeoHttpRequest struct {
 	*http.Request
+	// If true, the request is executed in its own transaction, so it can be retried
+	// after a transient error, even if the HTTP method is not idempotent.
+	retrySafe bool
 }
 
 func NewNeoHttpRequest(method, urlStr string, bodyBuf *bytes.Buffer) (*NeoHttpRequest, error) {
@@ -38,3 +41,16 @@ func NewNeoHttpRequest(method, urlStr string, bodyBuf *bytes.Buffer) (*NeoHttpRe
 	neoRequest.Request = req
 	return neoRequest, nil
 }
+
+// Prepares the request body to be sent again.
+func (n *NeoHttpRequest) rewind() error {
+	if n.Request.GetBody == nil {
+		return nil
+	}
+	body, err := n.Request.GetBody()
+	if err != nil {
+		return err
+	}
+	n.Request.Body = body
+	return nil
+}
@@ -2,6 +2,7 @@ package neo2go
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"regexp"
@@ -58,7 +59,7 @@ func (n *NeoBatch) queueRequestData(reqData *neoRequestData) *NeoResponse {
 	batchId := n.nextBatchId()
 	reqData.setBatchId(batchId)
 
-	resp := NewLocalErrorResponse(reqData.expectedStatus, nil)
+	resp := NewLocalErrorResponse(reqData.expectedStatus, reqData.err)
 	n.responses = append(n.responses, resp)
 	n.requests = append(n.requests, reqData)
 
@@ -70,7 +71,7 @@ func (n *NeoBatch) queueRequestDataWithResult(reqData *neoRequestData, result ba
 	reqData.setBatchId(batchId)
 	result.setBatchId(batchId)
 
-	resp := NewLocalErrorResponse(reqData.expectedStatus, nil)
+	resp := NewLocalErrorResponse(reqData.expectedStatus, reqData.err)
 	n.responses = append(n.responses, resp)
 	n.requests = append(n.requests, reqData)
 
@@ -104,6 +105,39 @@ func (n *NeoBatch) AddLabels(node *NeoNode, labels []string) *NeoResponse {
 	return n.queueRequestData(reqData)
 }
 
+func (n *NeoBatch) ReplaceLabels(node *NeoNode, labels []string) *NeoResponse {
+	reqData := n.service.builder.ReplaceLabels(node, labels)
+	return n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) RemoveLabel(node *NeoNode, label string) *NeoResponse {
+	reqData := n.service.builder.RemoveLabel(node, label)
+	return n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) GetLabelsForNode(node *NeoNode) (*[]string, *NeoResponse) {
+	result, reqData := n.service.builder.GetLabelsForNode(node)
+	return result, n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) GetNodesWithLabel(label string) (*[]*NeoNode, *NeoResponse) {
+	result, reqData := n.service.builder.GetNodesWithLabel(label)
+	return result, n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) GetNodesWithLabelAndProperty(label string, propertyKey string, propertyValue interface{}) (*[]*NeoNode, *NeoResponse) {
+	result, reqData, err := n.service.builder.GetNodesWithLabelAndProperty(label, propertyKey, propertyValue)
+	if err != nil {
+		return result, NewLocalErrorResponse(200, err)
+	}
+	return result, n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) GetAllLabels() (*[]string, *NeoResponse) {
+	result, reqData := n.service.builder.GetAllLabels()
+	return result, n.queueRequestData(reqData)
+}
+
 func (n *NeoBatch) GetNode(uri string) (*NeoNode, *NeoResponse) {
 	result, reqData := n.service.builder.GetNode(uri)
 	resp := n.queueRequestDataWithResult(reqData, result)
@@ -459,15 +493,109 @@ func (n *NeoBatch) CreateUniqueRelationshipWithPropertiesOrFail(index *NeoIndex,
 	return result, n.queueRequestDataWithResult(reqData, result)
 }
 
+// Auto-indexes
+
+func (n *NeoBatch) FindNodeByExactMatchingAutoIndex(key, value string) (*[]*NeoNode, *NeoResponse) {
+	result, reqData := n.service.builder.FindNodeByExactMatchingAutoIndex(key, value)
+	return result, n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) FindNodeByQueryingAutoIndex(query string) (*[]*NeoNode, *NeoResponse) {
+	result, reqData := n.service.builder.FindNodeByQueryingAutoIndex(query)
+	return result, n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) CreateNodeAutoIndexWithConfiguration(config interface{}) (*NeoIndex, *NeoResponse) {
+	result, reqData := n.service.builder.CreateNodeAutoIndexWithConfiguration(config)
+	return result, n.queueRequestDataWithResult(reqData, result)
+}
+
+func (n *NeoBatch) GetNodeAutoIndexStatus() (*bool, *NeoResponse) {
+	result, reqData := n.service.builder.GetNodeAutoIndexStatus()
+	return result, n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) SetNodeAutoIndexStatus(enabled bool) *NeoResponse {
+	reqData := n.service.builder.SetNodeAutoIndexStatus(enabled)
+	return n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) GetNodeAutoIndexProperties() (*[]string, *NeoResponse) {
+	result, reqData := n.service.builder.GetNodeAutoIndexProperties()
+	return result, n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) AddNodeAutoIndexProperty(propertyName string) *NeoResponse {
+	reqData := n.service.builder.AddNodeAutoIndexProperty(propertyName)
+	return n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) DeleteNodeAutoIndexProperty(propertyName string) *NeoResponse {
+	reqData := n.service.builder.DeleteNodeAutoIndexProperty(propertyName)
+	return n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) FindRelationshipByExactMatchingAutoIndex(key, value string) (*[]*NeoRelationship, *NeoResponse) {
+	result, reqData := n.service.builder.FindRelationshipByExactMatchingAutoIndex(key, value)
+	return result, n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) FindRelationshipByQueryingAutoIndex(query string) (*[]*NeoRelationship, *NeoResponse) {
+	result, reqData := n.service.builder.FindRelationshipByQueryingAutoIndex(query)
+	return result, n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) CreateRelationshipAutoIndexWithConfiguration(config interface{}) (*NeoIndex, *NeoResponse) {
+	result, reqData := n.service.builder.CreateRelationshipAutoIndexWithConfiguration(config)
+	return result, n.queueRequestDataWithResult(reqData, result)
+}
+
+func (n *NeoBatch) GetRelationshipAutoIndexStatus() (*bool, *NeoResponse) {
+	result, reqData := n.service.builder.GetRelationshipAutoIndexStatus()
+	return result, n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) SetRelationshipAutoIndexStatus(enabled bool) *NeoResponse {
+	reqData := n.service.builder.SetRelationshipAutoIndexStatus(enabled)
+	return n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) GetRelationshipAutoIndexProperties() (*[]string, *NeoResponse) {
+	result, reqData := n.service.builder.GetRelationshipAutoIndexProperties()
+	return result, n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) AddRelationshipAutoIndexProperty(propertyName string) *NeoResponse {
+	reqData := n.service.builder.AddRelationshipAutoIndexProperty(propertyName)
+	return n.queueRequestData(reqData)
+}
+
+func (n *NeoBatch) DeleteRelationshipAutoIndexProperty(propertyName string) *NeoResponse {
+	reqData := n.service.builder.DeleteRelationshipAutoIndexProperty(propertyName)
+	return n.queueRequestData(reqData)
+}
+
 func (n *NeoBatch) Commit() *NeoResponse {
+	return n.commit(n.service)
+}
+
+// Same as Commit, but the batch request is executed within the given context.
+func (n *NeoBatch) CommitWithContext(ctx context.Context) *NeoResponse {
+	return n.commit(n.service.WithContext(ctx))
+}
+
+func (n *NeoBatch) commit(service *GraphDatabaseService) *NeoResponse {
 	expectedStatus := 200
 	if n.currentBatchId == 0 {
-		return NewLocalErrorResponse(expectedStatus, fmt.Errorf("This batch does not contain any operations."))
+		return NewLocalErrorResponse(expectedStatus, ErrEmptyBatch)
 	}
 
 	elements := make([]*neoBatchElement, len(n.requests))
-	baseUrlLength := len(n.service.builder.root.Data.String())
+	baseUrlLength := len(service.builder.root.Data.String())
 	for i, reqData := range n.requests {
+		if reqData.err != nil {
+			return NewLocalErrorResponse(expectedStatus, fmt.Errorf("Invalid operation #%v: %w", reqData.batchId, reqData.err))
+		}
 		batchElem := new(neoBatchElement)
 		batchElem.Body = reqData.body
 		batchElem.Id = reqData.batchId
@@ -497,8 +625,12 @@ func (n *NeoBatch) Commit() *NeoResponse {
 		results[i] = resultElem
 	}
 
-	neoRequest, err := NewNeoHttpRequest("POST", n.service.builder.dataRoot.Batch.String(), bodyBuf)
-	neoResponse := n.service.execute(neoRequest, err, 200, &results)
+	neoRequest, err := NewNeoHttpRequest("POST", service.builder.dataRoot.Batch.String(), bodyBuf)
+	if err == nil {
+		// The whole batch is executed in one transaction.
+		neoRequest.retrySafe = true
+	}
+	neoResponse := service.execute(neoRequest, err, 200, &results)
 
 	for i, resultElem := range results {
 		n.responses[i].StatusCode = resultElem.Status
@@ -506,7 +638,7 @@ func (n *NeoBatch) Commit() *NeoResponse {
 
 		if resultElem.Status > 0 && resultElem.Status != n.requests[i].expectedStatus && resultElem.Status != 201 {
 			neoResponse.StatusCode = 600
-			neoResponse.Err = fmt.Errorf("The batch operation #%v has failed with status %d.\n", n.requests[i].batchId, resultElem.Status)
+			neoResponse.Err = fmt.Errorf("%w (operation #%v, status %d).", ErrBatchOperationFailed, n.requests[i].batchId, resultElem.Status)
 		}
 	}
 
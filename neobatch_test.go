@@ -1,6 +1,7 @@
 package neo2go
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -191,3 +192,44 @@ func TestBatchSetNodeProperty(t *testing.T) {
 		t.Fatalf("Invalid node2 property value: cannot convert to string (%v)", data2["name"])
 	}
 }
+
+func TestBatchWithEntitiesFromIds(t *testing.T) {
+	server, requests := newFakeBatchServer(t, func(method, to string) (int, string) {
+		if method == "POST" {
+			return 201, `{"self":"$URL/node/5"}`
+		}
+		if method == "GET" {
+			return 200, `[]`
+		}
+		return 204, ""
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	baseUrl := server.URL + "/db/data/"
+	node := NewNeoNodeFromId(baseUrl, 5)
+	rel := NewNeoRelationshipFromId(baseUrl, 3)
+	index := NewNeoIndexFromName(baseUrl, NeoNodeIndex, "my favorites")
+	if node.Id() != 5 || rel.Id() != 3 {
+		t.Fatalf("Unexpected ids: %d, %d", node.Id(), rel.Id())
+	}
+
+	batch := service.Batch()
+	batch.SetPropertyForNode(node, "name", "Bob")
+	batch.SetPropertyForRelationship(rel, "since", 2014)
+	batch.AddNodeToIndex(index, node, "name", "Bob")
+	batch.FindNodeByExactMatch(index, "name", "Bob")
+	checkResponseSucceeded(t, batch.Commit(), 200)
+
+	expected := []string{
+		`PUT /node/5/properties/name "Bob"`,
+		`PUT /relationship/3/properties/since 2014`,
+		`POST /index/node/my%20favorites/ {"key":"name","uri":"/node/5","value":"Bob"}`,
+		`GET /index/node/my%20favorites/name/Bob`,
+	}
+	if actual := strings.Join(*requests, "\n"); actual != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected requests:\n%v", actual)
+	}
+}
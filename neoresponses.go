@@ -2,6 +2,10 @@ package neo2go
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 )
 
 /*
@@ -41,18 +45,211 @@ func (n *NeoError) Error() string {
 	return n.Message
 }
 
+// Returns the status code of the error, with its metadata (see NeoStatusCode).
+func (n *NeoError) StatusCode() NeoStatusCode {
+	return NeoStatusCode(n.Code)
+}
+
+// Reports whether the error matches target, which can be a NeoStatusClass (e.g. ErrTransientError,
+// ErrSchemaCategory), or a NeoError with the same code (e.g. &NeoError{Code: Neo_ClientError_Schema_NoSuchIndex}).
+func (n *NeoError) Is(target error) bool {
+	switch t := target.(type) {
+	case *NeoStatusClass:
+		return t.matches(n.Code)
+	case *NeoError:
+		return t.Code != "" && t.Code == n.Code
+	}
+	return false
+}
+
+// A classification (e.g. ClientError) and/or a category (e.g. Schema) of Neo4j status codes.
+// The values are generated from status_codes.json (see ErrClientError, ErrSchemaCategory etc.).
+type NeoStatusClass struct {
+	Classification string
+	Category       string
+}
+
+func (n *NeoStatusClass) Error() string {
+	classification, category := n.Classification, n.Category
+	if classification == "" {
+		classification = "*"
+	}
+	if category == "" {
+		category = "*"
+	}
+	return fmt.Sprintf("Neo.%v.%v", classification, category)
+}
+
+func (n *NeoStatusClass) matches(code string) bool {
+	var classification, category string
+	if statusCode, known := LookupNeoStatusCode(code); known {
+		classification, category = statusCode.Classification(), statusCode.Category()
+	} else {
+		// A code which is newer than the status codes table.
+		parts := strings.Split(code, ".")
+		if len(parts) != 4 || parts[0] != "Neo" {
+			return false
+		}
+		classification, category = parts[1], parts[2]
+	}
+	return (n.Classification == "" || n.Classification == classification) && (n.Category == "" || n.Category == category)
+}
+
+// Errors which are not reported by the server (returned with status code 600).
+var (
+	ErrNotConnected           = errors.New("Cannot execute the request because the client is not connected.")
+	ErrMissingContentType     = errors.New("Server did not return a content-type for this response.")
+	ErrUnsupportedContentType = errors.New("Server has returned a response with unsupported content-type")
+	ErrMissingLocation        = errors.New("The server did not return traverser's location.")
+	ErrEmptyBatch             = errors.New("This batch does not contain any operations.")
+	ErrBatchOperationFailed   = errors.New("The batch operation has failed")
+)
+
+// Returned (wrapping the error reported by the server) when creating a schema index or constraint which already exists.
+var (
+	ErrIndexAlreadyExists      = &NeoError{Code: Neo_ClientError_Schema_IndexAlreadyExists, Message: "The schema index already exists."}
+	ErrConstraintAlreadyExists = &NeoError{Code: Neo_ClientError_Schema_ConstraintAlreadyExists, Message: "The constraint already exists."}
+)
+
 type NeoErrors struct {
+	Message           string     `json:"message"`
 	Errors            []NeoError `json:"errors"`
 	Exception         string     `json:"exception"`
 	ExceptionFullName string     `json:"fullname"`
+	StackTrace        []string   `json:"stacktrace"`
+	// The exception which has caused this one, if any.
+	Cause *NeoErrors `json:"cause"`
 }
 
+// Returns the codes and messages of the top-level errors or, if there are none, the exception and its message.
+// Use the %+v verb to print the whole exception chain with the stack traces.
 func (n *NeoErrors) Error() string {
-	s := ""
-	for _, n := range n.Errors {
-		s = s + " " + n.Error()
+	if len(n.Errors) > 0 {
+		s := make([]string, len(n.Errors))
+		for i := range n.Errors {
+			s[i] = n.Errors[i].Code + ": " + n.Errors[i].Message
+		}
+		return strings.Join(s, "; ")
+	}
+	if n.Exception != "" {
+		return n.Exception + ": " + n.Message
+	}
+	return n.Message
+}
+
+func (n *NeoErrors) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('+') {
+		io.WriteString(f, n.Error())
+		for cause := n; cause != nil; cause = cause.Cause {
+			prefix := "\n"
+			if cause != n {
+				prefix = "\nCaused by: "
+			}
+			name := cause.ExceptionFullName
+			if name == "" {
+				name = cause.Exception
+			}
+			fmt.Fprintf(f, "%s%s: %s", prefix, name, cause.Message)
+			for _, frame := range cause.StackTrace {
+				fmt.Fprintf(f, "\n\tat %s", frame)
+			}
+		}
+		return
+	}
+	io.WriteString(f, n.Error())
+}
+
+// Returns the individual errors and the cause, so that they can be matched with errors.Is and errors.As.
+func (n *NeoErrors) Unwrap() []error {
+	errs := make([]error, 0, len(n.Errors)+1)
+	for i := range n.Errors {
+		errs = append(errs, &n.Errors[i])
+	}
+	if n.Cause != nil {
+		errs = append(errs, n.Cause)
 	}
-	return s
+	return errs
+}
+
+// An error of a single statement of a transactional Cypher request.
+type CypherStatementError struct {
+	NeoError
+	// The index of the failed statement, within the request; -1 for the errors of the transaction
+	// itself (e.g. Neo.ClientError.Transaction.UnknownId), which are not caused by a statement.
+	StatementIndex int
+}
+
+func (c *CypherStatementError) Error() string {
+	if c.StatementIndex < 0 {
+		return fmt.Sprintf("%v (%v)", c.Message, c.Code)
+	}
+	return fmt.Sprintf("statement #%d: %v (%v)", c.StatementIndex, c.Message, c.Code)
+}
+
+// Returned (as NeoResponse.Err) when the server reports errors for a transactional Cypher request,
+// even though the response status is successful. The server executes the statements in order and
+// stops at the first failure, so the results of the preceding statements are still available.
+// After an error, the server rolls the transaction back.
+type CypherTransactionError struct {
+	Errors []CypherStatementError
+}
+
+// The failed statement is the one following the returned results, if such a statement has been sent.
+func newCypherTransactionError(errors []NeoError, failedStatement int, statementCount int) *CypherTransactionError {
+	transErr := &CypherTransactionError{Errors: make([]CypherStatementError, len(errors))}
+	for i, neoErr := range errors {
+		statementIndex := -1
+		if failedStatement < statementCount && isStatementError(neoErr.Code) {
+			statementIndex = failedStatement
+		}
+		transErr.Errors[i] = CypherStatementError{NeoError: neoErr, StatementIndex: statementIndex}
+	}
+	return transErr
+}
+
+// The errors reported for a whole request or transaction (e.g. an unknown transaction id, or a failed commit).
+var transactionErrorClasses = []error{
+	&NeoStatusClass{Category: "Request"},
+	&NeoStatusClass{Category: "Security"},
+	&NeoError{Code: Neo_ClientError_Transaction_ConcurrentRequest},
+	&NeoError{Code: Neo_ClientError_Transaction_EventHandlerThrewException},
+	&NeoError{Code: Neo_ClientError_Transaction_HookFailed},
+	&NeoError{Code: Neo_ClientError_Transaction_InvalidType},
+	&NeoError{Code: Neo_ClientError_Transaction_MarkedAsFailed},
+	&NeoError{Code: Neo_ClientError_Transaction_UnknownId},
+	&NeoError{Code: Neo_ClientError_Transaction_ValidationFailed},
+	&NeoError{Code: Neo_DatabaseError_Transaction_CouldNotBegin},
+	&NeoError{Code: Neo_DatabaseError_Transaction_CouldNotCommit},
+	&NeoError{Code: Neo_DatabaseError_Transaction_CouldNotRollback},
+	&NeoError{Code: Neo_DatabaseError_Transaction_CouldNotWriteToLog},
+	&NeoError{Code: Neo_DatabaseError_Transaction_ReleaseLocksFailed},
+}
+
+func isStatementError(code string) bool {
+	neoErr := &NeoError{Code: code}
+	for _, class := range transactionErrorClasses {
+		if neoErr.Is(class) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *CypherTransactionError) Error() string {
+	s := make([]string, len(c.Errors))
+	for i := range c.Errors {
+		s[i] = c.Errors[i].Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Returns the individual errors, so that they can be matched with errors.Is and errors.As.
+func (c *CypherTransactionError) Unwrap() []error {
+	errs := make([]error, len(c.Errors))
+	for i := range c.Errors {
+		errs[i] = &c.Errors[i]
+	}
+	return errs
 }
 
 type NeoResponse struct {
@@ -67,6 +264,9 @@ func NewLocalErrorResponse(expectedCode int, err error) *NeoResponse {
 }
 
 func (n *NeoResponse) Ok() bool {
+	if n.Err != nil {
+		return false
+	}
 	if n.ExpectedCode == 200 {
 		return n.StatusCode >= 200 && n.StatusCode < 300
 	}
@@ -77,6 +277,31 @@ func (n *NeoResponse) Created() bool {
 	return n.StatusCode == 201
 }
 
+// Returns true if the server has reported a retryable error (see NeoStatusCode.IsRetryable),
+// which means the request may succeed when executed again.
+func (n *NeoResponse) IsTransient() bool {
+	var neoErrors []NeoError
+	switch err := n.Err.(type) {
+	case *NeoErrors:
+		neoErrors = err.Errors
+	case *CypherTransactionError:
+		for _, stmtErr := range err.Errors {
+			neoErrors = append(neoErrors, stmtErr.NeoError)
+		}
+	}
+
+	for _, neoErr := range neoErrors {
+		if _, known := LookupNeoStatusCode(neoErr.Code); known {
+			if neoErr.StatusCode().IsRetryable() {
+				return true
+			}
+		} else if ErrTransientError.matches(neoErr.Code) {
+			return true
+		}
+	}
+	return false
+}
+
 type NeoRoot struct {
 	Management *UrlTemplate `json:"management"`
 	Data       *UrlTemplate `json:"data"`
@@ -101,6 +326,8 @@ type NeoDataRoot struct {
 type CypherResponse struct {
 	Columns []string
 	Data    [][]json.RawMessage
+	// Only returned for a profiled (see GraphDatabaseService.ProfileLegacyCypher) or explained statement.
+	Plan *CypherPlan `json:"plan"`
 }
 
 //go:generate go run struct_generator/main.go -f status_codes.json -o status_codes.go -p neo2go
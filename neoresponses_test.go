@@ -0,0 +1,156 @@
+package neo2go
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNeoErrorsMatchStatusClasses(t *testing.T) {
+	var err error = &NeoErrors{Errors: []NeoError{
+		{Code: Neo_ClientError_Schema_NoSuchIndex, Message: "No such index"},
+		{Code: Neo_TransientError_Transaction_DeadlockDetected, Message: "Deadlock"},
+	}}
+
+	for _, target := range []error{ErrClientError, ErrTransientError, ErrSchemaCategory, ErrTransactionCategory, &NeoError{Code: Neo_ClientError_Schema_NoSuchIndex}} {
+		if !errors.Is(err, target) {
+			t.Errorf("Expected the error to match %v", target)
+		}
+	}
+	for _, target := range []error{ErrDatabaseError, ErrStatementCategory, &NeoError{Code: Neo_ClientError_Schema_NoSuchConstraint}} {
+		if errors.Is(err, target) {
+			t.Errorf("The error should not match %v", target)
+		}
+	}
+
+	var neoErr *NeoError
+	if !errors.As(err, &neoErr) || neoErr.Code != Neo_ClientError_Schema_NoSuchIndex {
+		t.Errorf("Expected the first error, but got: %v", neoErr)
+	}
+
+	transErr := newCypherTransactionError([]NeoError{{Code: Neo_TransientError_Transaction_AcquireLockTimeout}}, 2, 3)
+	if resp := (&NeoResponse{StatusCode: 200, Err: transErr}); !resp.IsTransient() || resp.Ok() {
+		t.Errorf("Expected a failed transient response.")
+	}
+	var stmtErr *CypherStatementError
+	if !errors.As(fmt.Errorf("wrapped: %w", transErr), &stmtErr) || stmtErr.StatementIndex != 2 {
+		t.Errorf("Expected the statement error, but got: %v", stmtErr)
+	}
+}
+
+func TestTransactionErrorsWithoutStatement(t *testing.T) {
+	unknownId := NeoError{Code: Neo_ClientError_Transaction_UnknownId, Message: "Unrecognized transaction id."}
+	syntax := NeoError{Code: Neo_ClientError_Statement_InvalidSyntax, Message: "Invalid input."}
+
+	cases := []struct {
+		errors          []NeoError
+		failedStatement int
+		statementCount  int
+		expected        int
+	}{
+		{[]NeoError{syntax}, 1, 2, 1},
+		{[]NeoError{unknownId}, 0, 1, -1},
+		// A rollback or a commit without statements.
+		{[]NeoError{syntax}, 0, 0, -1},
+		{[]NeoError{syntax}, 2, 2, -1},
+	}
+	for _, c := range cases {
+		transErr := newCypherTransactionError(c.errors, c.failedStatement, c.statementCount)
+		if index := transErr.Errors[0].StatementIndex; index != c.expected {
+			t.Errorf("Expected the statement index %d for %v, but got %d", c.expected, c.errors[0].Code, index)
+		}
+	}
+
+	transErr := newCypherTransactionError([]NeoError{unknownId}, 0, 0)
+	if transErr.Error() != "Unrecognized transaction id. (Neo.ClientError.Transaction.UnknownId)" {
+		t.Errorf("Unexpected message: %v", transErr.Error())
+	}
+}
+
+func TestLocalErrors(t *testing.T) {
+	service := NewGraphDatabaseService()
+	_, resp := service.GetNode(databaseAddress + "/db/data/node/1")
+	if !errors.Is(resp.Err, ErrNotConnected) {
+		t.Errorf("Expected %v, but got: %v", ErrNotConnected, resp.Err)
+	}
+	if resp = service.Batch().Commit(); !errors.Is(resp.Err, ErrEmptyBatch) {
+		t.Errorf("Expected %v, but got: %v", ErrEmptyBatch, resp.Err)
+	}
+}
+
+func TestNeoStatusCodeMetadata(t *testing.T) {
+	code, known := LookupNeoStatusCode(Neo_TransientError_Transaction_DeadlockDetected)
+	if !known || code.Classification() != "TransientError" || code.Category() != "Transaction" || !code.IsRetryable() {
+		t.Errorf("Unexpected metadata of %v", code)
+	}
+	if code.Title() != "Deadlock detected" || code.Description() == "" {
+		t.Errorf("Unexpected title (%v) or description (%v)", code.Title(), code.Description())
+	}
+
+	code, known = LookupNeoStatusCode(Neo_ClientError_Statement_InvalidSyntax)
+	if !known || code.IsRetryable() {
+		t.Errorf("%v should not be retryable", code)
+	}
+
+	if _, known = LookupNeoStatusCode("Neo.TransientError.General.NewFailure"); known {
+		t.Errorf("Unknown codes should not be found")
+	}
+	resp := &NeoResponse{StatusCode: 200, Err: &NeoErrors{Errors: []NeoError{{Code: "Neo.TransientError.General.NewFailure"}}}}
+	if !resp.IsTransient() {
+		t.Errorf("Unknown transient codes should be treated as transient")
+	}
+}
+
+func TestNeoErrorsCauseChain(t *testing.T) {
+	body := `{
+		"message": "Node 43 already exists with label User and property \"email\"=[me@example.com]",
+		"exception": "CypherExecutionException",
+		"fullname": "org.neo4j.cypher.CypherExecutionException",
+		"stacktrace": ["org.neo4j.cypher.Execution.run(Execution.scala:12)"],
+		"cause": {
+			"message": "Node 43 already exists with label 0 and property 0=me@example.com",
+			"exception": "UniqueConstraintViolationKernelException",
+			"fullname": "org.neo4j.kernel.api.exceptions.schema.UniqueConstraintViolationKernelException",
+			"stacktrace": ["org.neo4j.kernel.Validation.check(Validation.java:34)"],
+			"errors": [{"code": "Neo.ClientError.Schema.ConstraintVerificationFailure", "message": "Node 43 already exists"}]
+		},
+		"errors": [{"code": "Neo.ClientError.Schema.ConstraintViolation", "message": "Node 43 already exists with label User"}]
+	}`
+	var neoErrors NeoErrors
+	if err := json.Unmarshal([]byte(body), &neoErrors); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := neoErrors.Error(); actual != "Neo.ClientError.Schema.ConstraintViolation: Node 43 already exists with label User" {
+		t.Errorf("Unexpected error message: %v", actual)
+	}
+
+	// The code of the cause is reached only through the Unwrap chain.
+	wrapped := fmt.Errorf("wrapped: %w", &neoErrors)
+	causeCode := &NeoError{Code: Neo_ClientError_Schema_ConstraintVerificationFailure}
+	if !errors.Is(wrapped, causeCode) {
+		t.Errorf("Expected the error of the cause to be found through Unwrap.")
+	}
+	var cause *NeoErrors
+	if unwrapped := neoErrors.Unwrap(); len(unwrapped) == 0 || !errors.As(unwrapped[len(unwrapped)-1], &cause) ||
+		cause.Exception != "UniqueConstraintViolationKernelException" {
+		t.Errorf("Unexpected cause: %v", cause)
+	}
+
+	verbose := fmt.Sprintf("%+v", &neoErrors)
+	for _, expected := range []string{
+		"org.neo4j.cypher.CypherExecutionException: Node 43",
+		"\tat org.neo4j.cypher.Execution.run(Execution.scala:12)",
+		"Caused by: org.neo4j.kernel.api.exceptions.schema.UniqueConstraintViolationKernelException: Node 43",
+		"\tat org.neo4j.kernel.Validation.check(Validation.java:34)",
+	} {
+		if !strings.Contains(verbose, expected) {
+			t.Errorf("Expected %q in the verbose message:\n%v", expected, verbose)
+		}
+	}
+	if concise := fmt.Sprintf("%v", &neoErrors); concise != neoErrors.Error() {
+		t.Errorf("Unexpected concise message: %v", concise)
+	}
+}
@@ -0,0 +1,615 @@
+package neo2go
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var ErrEntityNotSaved = errors.New("The related entity has not been saved (use WithCascade to save it along).")
+
+// Configures Save, Load and Delete.
+type MappingOption func(*mappingConfig)
+
+type mappingConfig struct {
+	cascade bool
+	depth   uint
+}
+
+// Save also saves the related entities, and Delete also deletes them (recursively).
+func WithCascade() MappingOption {
+	return func(c *mappingConfig) {
+		c.cascade = true
+	}
+}
+
+// Load also loads the related entities, up to the given number of relationships away. The default is 0.
+func WithDepth(depth uint) MappingOption {
+	return func(c *mappingConfig) {
+		c.depth = depth
+	}
+}
+
+/*
+Save, Load and Delete map structs to nodes, using `neo` struct tags:
+
+	type Person struct {
+		_       struct{}  `neo:"Person,label"`       // a label; defaults to the name of the type
+		Id      *int64    `neo:",id"`               // the id of the node; nil if it has not been saved yet
+		Name    string    `neo:"name"`              // a property
+		Email   string    `neo:"email,omitempty"`   // a property, which is not stored if empty
+		Age     int       ``                        // a property named "Age"
+		Secret  string    `neo:"-"`                 // not mapped
+		Boss    *Person   `neo:"REPORTS_TO,rel"`    // an outgoing relationship
+		Reports []*Person `neo:"REPORTS_TO,rel,in"` // incoming relationships
+	}
+
+The id field is a pointer, because Neo4j gives the id 0 to a node as well. A struct can have several
+label fields. The relationship fields must be pointers to mapped structs, or slices of such pointers;
+a nil field means the relationships were not loaded, and they are left untouched by Save. Set a slice field to an empty slice to remove all its relationships.
+*/
+type entityMapping struct {
+	labels        []string
+	idField       int
+	properties    []propertyMapping
+	relationships []relationshipMapping
+}
+
+type propertyMapping struct {
+	name      string
+	field     int
+	omitEmpty bool
+}
+
+type relationshipMapping struct {
+	relType   string
+	direction NeoTraversalDirection
+	field     int
+	many      bool
+	// The struct type of the related entities.
+	elemType reflect.Type
+}
+
+var entityMappings sync.Map
+
+func getEntityMapping(t reflect.Type) (*entityMapping, error) {
+	if mapping, ok := entityMappings.Load(t); ok {
+		return mapping.(*entityMapping), nil
+	}
+	mapping, err := newEntityMapping(t)
+	if err != nil {
+		return nil, err
+	}
+	entityMappings.Store(t, mapping)
+	return mapping, nil
+}
+
+func newEntityMapping(t reflect.Type) (*entityMapping, error) {
+	mapping := &entityMapping{idField: -1}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		parts := strings.Split(field.Tag.Get("neo"), ",")
+		name, options := parts[0], parts[1:]
+
+		switch {
+		case hasTagOption(options, "label"):
+			if name == "" {
+				return nil, fmt.Errorf("The label field %v.%v has no label name.", t, field.Name)
+			}
+			mapping.labels = append(mapping.labels, name)
+		case field.PkgPath != "" || field.Anonymous || name == "-":
+			continue
+		case hasTagOption(options, "id"):
+			if field.Type != reflect.TypeOf((*int64)(nil)) {
+				return nil, fmt.Errorf("The id field %v.%v must be an *int64.", t, field.Name)
+			}
+			mapping.idField = i
+		case hasTagOption(options, "rel"):
+			relationship, err := newRelationshipMapping(field, name, options)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid relationship field %v.%v: %v", t, field.Name, err)
+			}
+			relationship.field = i
+			mapping.relationships = append(mapping.relationships, *relationship)
+		default:
+			if name == "" {
+				name = field.Name
+			}
+			mapping.properties = append(mapping.properties, propertyMapping{name, i, hasTagOption(options, "omitempty")})
+		}
+	}
+
+	if mapping.idField < 0 {
+		return nil, fmt.Errorf("The type %v has no id field (tagged with `neo:\",id\"`).", t)
+	}
+	if len(mapping.labels) == 0 {
+		mapping.labels = []string{t.Name()}
+	}
+	return mapping, nil
+}
+
+func newRelationshipMapping(field reflect.StructField, relType string, options []string) (*relationshipMapping, error) {
+	if relType == "" {
+		return nil, fmt.Errorf("the relationship type is missing")
+	}
+	relationship := &relationshipMapping{relType: relType, direction: NeoTraversalOut}
+	if hasTagOption(options, "in") {
+		relationship.direction = NeoTraversalIn
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Slice {
+		relationship.many = true
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("the type must be a pointer to a struct, or a slice of such pointers")
+	}
+	relationship.elemType = t.Elem()
+	return relationship, nil
+}
+
+func hasTagOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// A struct being saved, loaded or deleted.
+type mappedEntity struct {
+	value   reflect.Value
+	mapping *entityMapping
+	node    *NeoNode
+}
+
+func newMappedEntity(entity interface{}) (*mappedEntity, error) {
+	value := reflect.ValueOf(entity)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("The entity must be a non-nil pointer to a struct, but got %T.", entity)
+	}
+	return newMappedEntityFromValue(value)
+}
+
+func newMappedEntityFromValue(value reflect.Value) (*mappedEntity, error) {
+	mapping, err := getEntityMapping(value.Type().Elem())
+	if err != nil {
+		return nil, err
+	}
+	return &mappedEntity{value: value, mapping: mapping}, nil
+}
+
+// Returns false, if the entity has not been saved yet (its id field is nil).
+func (m *mappedEntity) saved() bool {
+	return !m.value.Elem().Field(m.mapping.idField).IsNil()
+}
+
+func (m *mappedEntity) id() int64 {
+	return m.value.Elem().Field(m.mapping.idField).Elem().Int()
+}
+
+func (m *mappedEntity) setId(id int64) {
+	m.value.Elem().Field(m.mapping.idField).Set(reflect.ValueOf(&id))
+}
+
+func (m *mappedEntity) clearId() {
+	field := m.value.Elem().Field(m.mapping.idField)
+	field.Set(reflect.Zero(field.Type()))
+}
+
+// Returns the related entities, or nil if the relationship field is nil.
+func (m *mappedEntity) related(relationship *relationshipMapping) []reflect.Value {
+	field := m.value.Elem().Field(relationship.field)
+	if field.IsNil() {
+		return nil
+	}
+	if !relationship.many {
+		return []reflect.Value{field}
+	}
+	related := make([]reflect.Value, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		if elem := field.Index(i); !elem.IsNil() {
+			related = append(related, elem)
+		}
+	}
+	return related
+}
+
+func (m *mappedEntity) properties() map[string]interface{} {
+	properties := make(map[string]interface{})
+	for _, property := range m.mapping.properties {
+		field := m.value.Elem().Field(property.field)
+		if property.omitEmpty && field.IsZero() {
+			continue
+		}
+		switch field.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			// Neo4j cannot store null values.
+			if field.IsNil() {
+				continue
+			}
+		}
+		properties[property.name] = field.Interface()
+	}
+	return properties
+}
+
+func (m *mappedEntity) setProperties(data json.RawMessage) error {
+	var values map[string]json.RawMessage
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+	}
+	for _, property := range m.mapping.properties {
+		field := m.value.Elem().Field(property.field)
+		raw, ok := values[property.name]
+		if !ok {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+		if err := decodePropertyValue(raw, field); err != nil {
+			return fmt.Errorf("Could not decode the property '%v': %v", property.name, err)
+		}
+	}
+	return nil
+}
+
+// Collects the entity and, if cascade is set, all the entities reachable through its relationship fields.
+func collectEntities(entity interface{}, cascade bool) ([]*mappedEntity, error) {
+	root, err := newMappedEntity(entity)
+	if err != nil {
+		return nil, err
+	}
+	entities := []*mappedEntity{root}
+	visited := map[uintptr]bool{root.value.Pointer(): true}
+	for i := 0; cascade && i < len(entities); i++ {
+		current := entities[i]
+		for r := range current.mapping.relationships {
+			for _, value := range current.related(&current.mapping.relationships[r]) {
+				if visited[value.Pointer()] {
+					continue
+				}
+				visited[value.Pointer()] = true
+				related, err := newMappedEntityFromValue(value)
+				if err != nil {
+					return nil, err
+				}
+				entities = append(entities, related)
+			}
+		}
+	}
+	return entities, nil
+}
+
+// Returns a node with the url templates built from the id, without fetching it.
+func (g *GraphDatabaseService) nodeFromId(id int64) (*NeoNode, error) {
+	if g.builder.root.Data == nil || g.builder.dataRoot.Node == nil {
+		return nil, ErrNotConnected
+	}
+	return NewNeoNodeFromId(g.builder.root.Data.String(), id), nil
+}
+
+func idFromUrl(template *UrlTemplate) int64 {
+	if template == nil {
+		return 0
+	}
+	_, file := path.Split(template.String())
+	id, _ := strconv.ParseInt(file, 10, 64)
+	return id
+}
+
+// Returns the id of the node at the other end of the relationship.
+func otherNodeId(rel *NeoRelationship, direction NeoTraversalDirection) int64 {
+	if direction == NeoTraversalIn {
+		return idFromUrl(rel.Start)
+	}
+	return idFromUrl(rel.End)
+}
+
+// Creates or updates the node of the entity (its properties and labels), and its relationships,
+// in a single batch. The ids of the created nodes are set on the entities.
+//
+// Without WithCascade, the related entities must have been saved before. With it, all
+// the entities reachable through the relationship fields are saved as well.
+func (g *GraphDatabaseService) Save(entity interface{}, options ...MappingOption) *NeoResponse {
+	var config mappingConfig
+	for _, option := range options {
+		option(&config)
+	}
+	entities, err := collectEntities(entity, config.cascade)
+	if err != nil {
+		return NewLocalErrorResponse(200, err)
+	}
+
+	type relationshipKey struct {
+		entity       *mappedEntity
+		relationship *relationshipMapping
+	}
+	existing := make(map[relationshipKey]*[]*NeoRelationship)
+	lookup := g.Batch()
+	for _, e := range entities {
+		if !e.saved() {
+			continue
+		}
+		if e.node, err = g.nodeFromId(e.id()); err != nil {
+			return NewLocalErrorResponse(200, err)
+		}
+		for r := range e.mapping.relationships {
+			relationship := &e.mapping.relationships[r]
+			if e.related(relationship) == nil {
+				continue
+			}
+			rels, resp := lookup.GetRelationshipsWithTypesForNode(e.node, relationship.direction, []string{relationship.relType})
+			if resp.Err != nil {
+				return resp
+			}
+			existing[relationshipKey{e, relationship}] = rels
+		}
+	}
+	if len(existing) > 0 {
+		if resp := lookup.Commit(); !resp.Ok() {
+			return resp
+		}
+	}
+
+	batch := g.Batch()
+	byPointer := make(map[uintptr]*mappedEntity)
+	for _, e := range entities {
+		byPointer[e.value.Pointer()] = e
+		if e.node == nil {
+			e.node, _ = batch.CreateNodeWithProperties(e.properties())
+		} else {
+			batch.ReplacePropertiesForNode(e.node, e.properties())
+		}
+		batch.AddLabels(e.node, e.mapping.labels)
+	}
+
+	// The same relationship can be declared on both of its nodes, so the relationships
+	// are identified by their ends, and deleted only if neither of the nodes keeps them.
+	type relationshipEnds struct {
+		start, end interface{}
+		relType    string
+	}
+	created := make(map[relationshipEnds]bool)
+	kept := make(map[int64]bool)
+	var stale []*NeoRelationship
+	for _, e := range entities {
+		for r := range e.mapping.relationships {
+			relationship := &e.mapping.relationships[r]
+			related := e.related(relationship)
+			if related == nil {
+				continue
+			}
+
+			existingByNode := make(map[int64]*NeoRelationship)
+			if rels := existing[relationshipKey{e, relationship}]; rels != nil {
+				for _, rel := range *rels {
+					existingByNode[otherNodeId(rel, relationship.direction)] = rel
+				}
+			}
+
+			wanted := make(map[int64]bool)
+			for _, value := range related {
+				target := byPointer[value.Pointer()]
+				var targetKey interface{} = value.Pointer()
+				if target == nil {
+					if target, err = newMappedEntityFromValue(value); err != nil {
+						return NewLocalErrorResponse(200, err)
+					}
+					if !target.saved() {
+						return NewLocalErrorResponse(200, ErrEntityNotSaved)
+					}
+					if target.node, err = g.nodeFromId(target.id()); err != nil {
+						return NewLocalErrorResponse(200, err)
+					}
+				}
+				if target.saved() {
+					targetKey = target.id()
+					wanted[target.id()] = true
+					if rel := existingByNode[target.id()]; rel != nil {
+						kept[rel.Id()] = true
+						continue
+					}
+				}
+
+				var sourceKey interface{} = e.value.Pointer()
+				if e.saved() {
+					sourceKey = e.id()
+				}
+				source := e
+				ends := relationshipEnds{sourceKey, targetKey, relationship.relType}
+				if relationship.direction == NeoTraversalIn {
+					source, target = target, source
+					ends = relationshipEnds{targetKey, sourceKey, relationship.relType}
+				}
+				if !created[ends] {
+					created[ends] = true
+					batch.CreateRelationshipWithType(source.node, target.node, relationship.relType)
+				}
+			}
+
+			for nodeId, rel := range existingByNode {
+				if !wanted[nodeId] {
+					stale = append(stale, rel)
+				}
+			}
+		}
+	}
+	deleted := make(map[int64]bool)
+	for _, rel := range stale {
+		if !kept[rel.Id()] && !deleted[rel.Id()] {
+			deleted[rel.Id()] = true
+			batch.DeleteRelationship(rel)
+		}
+	}
+
+	resp := batch.Commit()
+	if resp.Ok() {
+		for _, e := range entities {
+			if !e.saved() {
+				e.setId(e.node.Id())
+			}
+		}
+	}
+	return resp
+}
+
+// Loads the node with the given id into dest (a pointer to a mapped struct).
+// With WithDepth, the related entities are loaded as well; the entities are loaded once,
+// so cycles in the graph are preserved as cycles of pointers.
+func (g *GraphDatabaseService) Load(id int64, dest interface{}, options ...MappingOption) *NeoResponse {
+	var config mappingConfig
+	for _, option := range options {
+		option(&config)
+	}
+	root, err := newMappedEntity(dest)
+	if err != nil {
+		return NewLocalErrorResponse(200, err)
+	}
+	root.setId(id)
+
+	type loadedKey struct {
+		id int64
+		t  reflect.Type
+	}
+	loaded := map[loadedKey]*mappedEntity{{id, root.value.Type()}: root}
+	frontier := []*mappedEntity{root}
+	var resp *NeoResponse
+	for level := uint(0); len(frontier) > 0; level++ {
+		batch := g.Batch()
+		fetched := make([]*NeoNode, len(frontier))
+		for i, e := range frontier {
+			if e.node, err = g.nodeFromId(e.id()); err != nil {
+				return NewLocalErrorResponse(200, err)
+			}
+			fetched[i], _ = batch.GetNode(e.node.Self.String())
+		}
+		if resp = batch.Commit(); !resp.Ok() {
+			return resp
+		}
+		for i, e := range frontier {
+			if err := e.setProperties(fetched[i].Data); err != nil {
+				return NewLocalErrorResponse(resp.ExpectedCode, err)
+			}
+		}
+		if level == config.depth {
+			break
+		}
+
+		type relationshipsResult struct {
+			entity       *mappedEntity
+			relationship *relationshipMapping
+			rels         *[]*NeoRelationship
+		}
+		var results []relationshipsResult
+		batch = g.Batch()
+		for _, e := range frontier {
+			for r := range e.mapping.relationships {
+				relationship := &e.mapping.relationships[r]
+				rels, resp := batch.GetRelationshipsWithTypesForNode(e.node, relationship.direction, []string{relationship.relType})
+				if resp.Err != nil {
+					return resp
+				}
+				results = append(results, relationshipsResult{e, relationship, rels})
+			}
+		}
+		if len(results) == 0 {
+			break
+		}
+		if resp = batch.Commit(); !resp.Ok() {
+			return resp
+		}
+
+		frontier = nil
+		for _, result := range results {
+			field := result.entity.value.Elem().Field(result.relationship.field)
+			field.Set(reflect.Zero(field.Type()))
+			if result.relationship.many {
+				field.Set(reflect.MakeSlice(field.Type(), 0, len(*result.rels)))
+			}
+			for _, rel := range *result.rels {
+				otherId := otherNodeId(rel, result.relationship.direction)
+				key := loadedKey{otherId, reflect.PtrTo(result.relationship.elemType)}
+				related := loaded[key]
+				if related == nil {
+					if related, err = newMappedEntityFromValue(reflect.New(result.relationship.elemType)); err != nil {
+						return NewLocalErrorResponse(resp.ExpectedCode, err)
+					}
+					related.setId(otherId)
+					loaded[key] = related
+					frontier = append(frontier, related)
+				}
+				if result.relationship.many {
+					field.Set(reflect.Append(field, related.value))
+				} else if field.IsNil() {
+					field.Set(related.value)
+				}
+			}
+		}
+	}
+	return resp
+}
+
+// Deletes the node of the entity, together with all its relationships, and sets the id of
+// the entity to nil. With WithCascade, the related entities are deleted as well.
+func (g *GraphDatabaseService) Delete(entity interface{}, options ...MappingOption) *NeoResponse {
+	var config mappingConfig
+	for _, option := range options {
+		option(&config)
+	}
+	collected, err := collectEntities(entity, config.cascade)
+	if err != nil {
+		return NewLocalErrorResponse(200, err)
+	}
+
+	var entities []*mappedEntity
+	var allRels []*[]*NeoRelationship
+	lookup := g.Batch()
+	for _, e := range collected {
+		if !e.saved() {
+			continue
+		}
+		if e.node, err = g.nodeFromId(e.id()); err != nil {
+			return NewLocalErrorResponse(200, err)
+		}
+		rels, _ := lookup.GetRelationshipsForNode(e.node, NeoTraversalAll)
+		allRels = append(allRels, rels)
+		entities = append(entities, e)
+	}
+	if len(entities) == 0 {
+		return NewLocalErrorResponse(200, ErrEntityNotSaved)
+	}
+	if resp := lookup.Commit(); !resp.Ok() {
+		return resp
+	}
+
+	batch := g.Batch()
+	deleted := make(map[int64]bool)
+	for _, rels := range allRels {
+		for _, rel := range *rels {
+			if !deleted[rel.Id()] {
+				deleted[rel.Id()] = true
+				batch.DeleteRelationship(rel)
+			}
+		}
+	}
+	for _, e := range entities {
+		batch.DeleteNode(e.node)
+	}
+
+	resp := batch.Commit()
+	if resp.Ok() {
+		for _, e := range entities {
+			e.clearId()
+		}
+	}
+	return resp
+}
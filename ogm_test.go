@@ -0,0 +1,233 @@
+package neo2go
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type ogmPerson struct {
+	_       struct{}     `neo:"Person,label"`
+	_       struct{}     `neo:"Employee,label"`
+	Id      *int64       `neo:",id"`
+	Name    string       `neo:"name"`
+	Email   string       `neo:"email,omitempty"`
+	Age     int          ``
+	Secret  string       `neo:"-"`
+	Boss    *ogmPerson   `neo:"REPORTS_TO,rel"`
+	Reports []*ogmPerson `neo:"REPORTS_TO,rel,in"`
+}
+
+// Serves the batch requests with the given function, and records them.
+func newFakeBatchServer(t *testing.T, respond func(method, to string) (int, string)) (*httptest.Server, *[]string) {
+	var requests []string
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/db/data/batch" {
+			t.Fatalf("Unexpected request: %v %v", r.Method, r.URL)
+		}
+		var elements []neoBatchElement
+		if err := json.NewDecoder(r.Body).Decode(&elements); err != nil {
+			t.Fatalf("Invalid batch request: %v", err)
+		}
+
+		results := make([]string, len(elements))
+		for i, element := range elements {
+			request := element.Method + " " + element.To
+			if element.Body != nil {
+				body, _ := json.Marshal(element.Body)
+				request += " " + strings.ReplaceAll(string(body), "http://"+r.Host+"/db/data", "")
+			}
+			requests = append(requests, request)
+
+			status, body := respond(element.Method, element.To)
+			body = strings.ReplaceAll(body, "$URL", "http://"+r.Host+"/db/data")
+			if body == "" {
+				body = "null"
+			}
+			results[i] = fmt.Sprintf(`{"id":%d,"status":%d,"body":%s}`, element.Id, status, body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, "[%s]", strings.Join(results, ","))
+	})
+	return server, &requests
+}
+
+func TestEntityMapping(t *testing.T) {
+	mapping, err := getEntityMapping(reflect.TypeOf(ogmPerson{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(mapping.labels, ",") != "Person,Employee" {
+		t.Errorf("Unexpected labels: %v", mapping.labels)
+	}
+	var properties []string
+	for _, property := range mapping.properties {
+		properties = append(properties, property.name)
+	}
+	if strings.Join(properties, ",") != "name,email,Age" {
+		t.Errorf("Unexpected properties: %v", properties)
+	}
+	if len(mapping.relationships) != 2 || mapping.relationships[1].direction != NeoTraversalIn || !mapping.relationships[1].many {
+		t.Errorf("Unexpected relationships: %+v", mapping.relationships)
+	}
+
+	var invalid struct {
+		Name string
+	}
+	if _, err := getEntityMapping(reflect.TypeOf(invalid)); err == nil {
+		t.Errorf("Expected an error for a struct without an id field.")
+	}
+	var intId struct {
+		Id int64 `neo:",id"`
+	}
+	if _, err := getEntityMapping(reflect.TypeOf(intId)); err == nil {
+		t.Errorf("Expected an error for an id field, which is not a pointer.")
+	}
+}
+
+func TestSaveEntity(t *testing.T) {
+	server, requests := newFakeBatchServer(t, func(method, to string) (int, string) {
+		switch {
+		case method == "POST" && to == "/node":
+			return 201, `{"self":"$URL/node/11","data":{}}`
+		case method == "POST" && strings.HasSuffix(to, "/relationships"):
+			return 201, `{"self":"$URL/relationship/5","type":"REPORTS_TO"}`
+		case method == "GET":
+			return 200, `[{"self":"$URL/relationship/3","start":"$URL/node/9","end":"$URL/node/7","type":"REPORTS_TO"}]`
+		}
+		return 204, ""
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	boss := &ogmPerson{Name: "Bob", Reports: []*ogmPerson{}}
+	alice := &ogmPerson{Name: "Alice", Age: 30, Secret: "x", Boss: boss}
+	boss.Reports = append(boss.Reports, alice)
+	if resp := service.Save(alice); !errors.Is(resp.Err, ErrEntityNotSaved) {
+		t.Fatalf("Expected %v, but got: %v", ErrEntityNotSaved, resp.Err)
+	}
+
+	bossId := int64(7)
+	boss.Id = &bossId
+	checkResponseSucceeded(t, service.Save(alice, WithCascade()), 200)
+	if alice.Id == nil || *alice.Id != 11 {
+		t.Errorf("Expected the id to be set, but got %v", alice.Id)
+	}
+
+	// The REPORTS_TO relationship is declared on both nodes, so it is created once.
+	expected := []string{
+		"GET /node/7/relationships/in/REPORTS_TO",
+		`POST /node {"Age":30,"name":"Alice"}`,
+		`POST {1}/labels ["Person","Employee"]`,
+		`PUT /node/7/properties {"Age":0,"name":"Bob"}`,
+		`POST /node/7/labels ["Person","Employee"]`,
+		`POST {1}/relationships {"to":"/node/7","type":"REPORTS_TO"}`,
+		"DELETE /relationship/3",
+	}
+	if actual := strings.Join(*requests, "\n"); actual != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected requests:\n%v", actual)
+	}
+}
+
+func TestLoadAndDeleteEntity(t *testing.T) {
+	server, requests := newFakeBatchServer(t, func(method, to string) (int, string) {
+		if method == "DELETE" {
+			return 204, ""
+		}
+		switch to {
+		case "/node/7":
+			return 200, `{"self":"$URL/node/7","data":{"name":"Bob","Age":50}}`
+		case "/node/11":
+			return 200, `{"self":"$URL/node/11","data":{"name":"Alice","email":"alice@example.com"}}`
+		case "/node/11/relationships/out/REPORTS_TO", "/node/7/relationships/in/REPORTS_TO", "/node/7/relationships/all":
+			return 200, `[{"self":"$URL/relationship/3","start":"$URL/node/11","end":"$URL/node/7","type":"REPORTS_TO"}]`
+		}
+		return 200, `[]`
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	alice := new(ogmPerson)
+	checkResponseSucceeded(t, service.Load(11, alice, WithDepth(2)), 200)
+	if alice.Name != "Alice" || alice.Email != "alice@example.com" || alice.Boss == nil || alice.Boss.Name != "Bob" {
+		t.Fatalf("Unexpected entity: %+v", alice)
+	}
+	if len(alice.Reports) != 0 || len(alice.Boss.Reports) != 1 || alice.Boss.Reports[0] != alice {
+		t.Fatalf("Expected the relationships to be loaded, but got: %+v", alice.Boss)
+	}
+
+	*requests = nil
+	checkResponseSucceeded(t, service.Delete(alice.Boss), 200)
+	if alice.Boss.Id != nil {
+		t.Errorf("Expected the id to be reset, but got %d", *alice.Boss.Id)
+	}
+	expected := []string{
+		"GET /node/7/relationships/all",
+		"DELETE /relationship/3",
+		"DELETE /node/7",
+	}
+	if actual := strings.Join(*requests, "\n"); actual != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected requests:\n%v", actual)
+	}
+}
+
+func TestEntityWithIdZero(t *testing.T) {
+	server, requests := newFakeBatchServer(t, func(method, to string) (int, string) {
+		switch {
+		case method == "POST" && to == "/node":
+			return 201, `{"self":"$URL/node/0","data":{}}`
+		case method == "GET" && to == "/node/0":
+			return 200, `{"self":"$URL/node/0","data":{"name":"Alice"}}`
+		case method == "GET":
+			return 200, `[]`
+		}
+		return 204, ""
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+
+	alice := &ogmPerson{Name: "Alice"}
+	checkResponseSucceeded(t, service.Save(alice), 200)
+	if alice.Id == nil || *alice.Id != 0 {
+		t.Fatalf("Expected the id 0 to be set, but got %v", alice.Id)
+	}
+	checkResponseSucceeded(t, service.Save(alice), 200)
+
+	loaded := new(ogmPerson)
+	checkResponseSucceeded(t, service.Load(0, loaded), 200)
+	if loaded.Id == nil || *loaded.Id != 0 || loaded.Name != "Alice" {
+		t.Fatalf("Unexpected entity: %+v", loaded)
+	}
+
+	checkResponseSucceeded(t, service.Delete(loaded), 200)
+	if loaded.Id != nil {
+		t.Errorf("Expected the id to be reset, but got %d", *loaded.Id)
+	}
+	if resp := service.Delete(loaded); !errors.Is(resp.Err, ErrEntityNotSaved) {
+		t.Errorf("Expected %v for a deleted entity, but got: %v", ErrEntityNotSaved, resp.Err)
+	}
+
+	expected := []string{
+		`POST /node {"Age":0,"name":"Alice"}`,
+		`POST {1}/labels ["Person","Employee"]`,
+		`PUT /node/0/properties {"Age":0,"name":"Alice"}`,
+		`POST /node/0/labels ["Person","Employee"]`,
+		"GET /node/0",
+		"GET /node/0/relationships/all",
+		"DELETE /node/0",
+	}
+	if actual := strings.Join(*requests, "\n"); actual != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected requests:\n%v", actual)
+	}
+}
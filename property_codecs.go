@@ -0,0 +1,480 @@
+package neo2go
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Converts the values of a Go type, which Neo4j cannot store, to property values (e.g. strings),
+// and back. The codecs are used when sending properties (CreateNodeWithProperties, SetPropertyForNode,
+// ReplacePropertiesForNode etc.), and when decoding them (ParseData, and scanning Cypher results).
+//
+// The default encodings are:
+//
+//	time.Time      a string in the RFC 3339 format, in UTC and with all the nanosecond digits
+//	               (e.g. "2014-01-02T03:04:05.000000000Z"), so that the strings sort like the times
+//	time.Duration  a long, the number of nanoseconds
+//	[16]byte       a string in the UUID format (e.g. "6ba7b810-9dad-11d1-80b4-00c04fd430c8");
+//	               also used for the other types with the same underlying type
+//	big.Rat        a string, as returned by RatString (e.g. "1/3")
+type PropertyCodec interface {
+	// Returns the property value (one of the types accepted by ValidatePropertyValue).
+	EncodeProperty(value interface{}) (interface{}, error)
+	// Decodes the property (a bool, string, json.Number or []interface{}) into dest,
+	// which is a pointer to the type of the codec.
+	DecodeProperty(property interface{}, dest interface{}) error
+}
+
+// Implemented by types, which encode themselves as property values.
+type PropertyMarshaler interface {
+	MarshalProperty() (interface{}, error)
+}
+
+// Implemented by types, which decode themselves from property values
+// (a bool, string, json.Number or []interface{}).
+type PropertyUnmarshaler interface {
+	UnmarshalProperty(property interface{}) error
+}
+
+var (
+	propertyMarshalerType   = reflect.TypeOf((*PropertyMarshaler)(nil)).Elem()
+	propertyUnmarshalerType = reflect.TypeOf((*PropertyUnmarshaler)(nil)).Elem()
+)
+
+var propertyCodecs = struct {
+	sync.RWMutex
+	byType map[reflect.Type]PropertyCodec
+}{byType: map[reflect.Type]PropertyCodec{
+	reflect.TypeOf(time.Time{}):      timeCodec{},
+	reflect.TypeOf(time.Duration(0)): durationCodec{},
+	reflect.TypeOf(big.Rat{}):        ratCodec{},
+}}
+
+// Registers the codec for the type of the example value (e.g. RegisterPropertyCodec(Money{}, moneyCodec)),
+// replacing the current one. A nil codec removes the registration.
+func RegisterPropertyCodec(example interface{}, codec PropertyCodec) {
+	propertyCodecs.Lock()
+	defer propertyCodecs.Unlock()
+	t := reflect.TypeOf(example)
+	if codec == nil {
+		delete(propertyCodecs.byType, t)
+	} else {
+		propertyCodecs.byType[t] = codec
+	}
+}
+
+func propertyCodecFor(t reflect.Type) PropertyCodec {
+	propertyCodecs.RLock()
+	codec := propertyCodecs.byType[t]
+	propertyCodecs.RUnlock()
+	if codec == nil && t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8 {
+		codec = uuidCodec{}
+	}
+	return codec
+}
+
+// Returns true, if the values of the type are encoded by a codec or by themselves.
+func hasPropertyCodec(t reflect.Type) bool {
+	return t.Implements(propertyMarshalerType) || reflect.PtrTo(t).Implements(propertyMarshalerType) ||
+		reflect.PtrTo(t).Implements(propertyUnmarshalerType) || propertyCodecFor(t) != nil
+}
+
+// Encodes the value of a single property.
+func encodePropertyValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return encodePropertyReflectValue(reflect.ValueOf(value))
+}
+
+func encodePropertyReflectValue(v reflect.Value) (interface{}, error) {
+	if v.Kind() == reflect.Ptr && !v.IsNil() && !v.Type().Implements(propertyMarshalerType) && propertyCodecFor(v.Type()) == nil {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Interface || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodePropertyReflectValue(v.Elem())
+	}
+
+	if marshaler, ok := v.Interface().(PropertyMarshaler); ok {
+		return marshaler.MarshalProperty()
+	}
+	if reflect.PtrTo(v.Type()).Implements(propertyMarshalerType) {
+		if !v.CanAddr() {
+			// E.g. a map value, or a field of a struct passed by value; its copy is addressable.
+			addressable := reflect.New(v.Type()).Elem()
+			addressable.Set(v)
+			v = addressable
+		}
+		return v.Addr().Interface().(PropertyMarshaler).MarshalProperty()
+	}
+	if codec := propertyCodecFor(v.Type()); codec != nil {
+		return codec.EncodeProperty(v.Interface())
+	}
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && hasPropertyCodec(v.Type().Elem()) {
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			encoded, err := encodePropertyReflectValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values[i] = encoded
+		}
+		return values, nil
+	}
+	return v.Interface(), nil
+}
+
+// Encodes the properties (a map with string keys, or a struct encoded like by encoding/json),
+// so that they can be sent as a JSON object. The values of other types are returned unchanged.
+func encodeProperties(properties interface{}) (interface{}, error) {
+	if properties == nil {
+		return nil, nil
+	}
+	v := reflect.ValueOf(properties)
+	for v.Kind() == reflect.Ptr && !v.IsNil() && !v.Type().Implements(jsonMarshalerType) {
+		v = v.Elem()
+	}
+	if v.Type().Implements(jsonMarshalerType) {
+		return properties, nil
+	}
+
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		if v.IsNil() {
+			return properties, nil
+		}
+		encoded := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value, err := encodePropertyReflectValue(iter.Value())
+			if err != nil {
+				return nil, fmt.Errorf("Could not encode the property '%v': %v", iter.Key().String(), err)
+			}
+			encoded[iter.Key().String()] = value
+		}
+		return encoded, nil
+	case v.Kind() == reflect.Struct && !hasPropertyCodec(v.Type()):
+		encoded := make(map[string]interface{})
+		for _, field := range jsonFieldsOf(v.Type()) {
+			fieldValue, err := v.FieldByIndexErr(field.index)
+			if err != nil || !fieldValue.CanInterface() {
+				// A nil embedded pointer, or a field of an unexported embedded struct.
+				continue
+			}
+			if field.omitEmpty && isEmptyJsonValue(fieldValue) {
+				continue
+			}
+			value, err := encodePropertyReflectValue(fieldValue)
+			if err != nil {
+				return nil, fmt.Errorf("Could not encode the property '%v': %v", field.name, err)
+			}
+			encoded[field.name] = value
+		}
+		return encoded, nil
+	}
+	return properties, nil
+}
+
+// Encodes and validates the properties of a request.
+func prepareProperties(properties interface{}) (interface{}, error) {
+	encoded, err := encodeProperties(properties)
+	if err != nil {
+		return properties, err
+	}
+	return encoded, ValidateProperties(encoded)
+}
+
+// Encodes and validates the value of a single property of a request.
+func preparePropertyValue(key string, value interface{}) (interface{}, error) {
+	encoded, err := encodePropertyValue(value)
+	if err != nil {
+		return value, fmt.Errorf("Could not encode the property '%v': %v", key, err)
+	}
+	return encoded, validatePropertyForKey(key, encoded)
+}
+
+// Decodes a property value into v.
+func decodePropertyValue(raw json.RawMessage, v reflect.Value) error {
+	t := v.Type()
+	if t.Kind() == reflect.Ptr && hasPropertyCodec(t.Elem()) && propertyCodecFor(t) == nil &&
+		!reflect.PtrTo(t).Implements(propertyUnmarshalerType) {
+		if isJsonNull(raw) {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return decodePropertyValue(raw, v.Elem())
+	}
+
+	unmarshaler, isUnmarshaler := v.Addr().Interface().(PropertyUnmarshaler)
+	codec := propertyCodecFor(t)
+	switch {
+	case isUnmarshaler || codec != nil:
+		var property interface{}
+		if err := decodeJsonWithNumbers(raw, &property); err != nil {
+			return err
+		}
+		if isUnmarshaler {
+			return unmarshaler.UnmarshalProperty(property)
+		}
+		return codec.DecodeProperty(property, v.Addr().Interface())
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && hasPropertyCodec(t.Elem()):
+		if isJsonNull(raw) {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return err
+		}
+		if t.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(t, len(elems), len(elems)))
+		} else if len(elems) != t.Len() {
+			return fmt.Errorf("Expected %d elements, but got %d.", t.Len(), len(elems))
+		}
+		for i, elem := range elems {
+			if err := decodePropertyValue(elem, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return decodeJsonWithNumbers(raw, v.Addr().Interface())
+}
+
+// Decodes the properties (a JSON object) into result, using the codecs for the values of
+// the struct fields (following the json tags) and of the maps with string keys.
+func decodeProperties(data []byte, result interface{}) error {
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return decodeJsonWithNumbers(data, result)
+	}
+	v = v.Elem()
+	if isJsonNull(data) || reflect.PtrTo(v.Type()).Implements(jsonUnmarshalerType) || hasPropertyCodec(v.Type()) {
+		return decodeJsonWithNumbers(data, result)
+	}
+
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(values)))
+		}
+		for key, raw := range values {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := decodePropertyValue(raw, elem); err != nil {
+				return fmt.Errorf("Could not decode the property '%v': %v", key, err)
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+		}
+		return nil
+	case v.Kind() == reflect.Struct:
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		fields := jsonFieldsOf(v.Type())
+		for key, raw := range values {
+			field := matchJsonField(fields, key)
+			if field == nil {
+				continue
+			}
+			fieldValue, err := fieldByIndexAlloc(v, field.index)
+			if err != nil {
+				return err
+			}
+			if err := decodePropertyValue(raw, fieldValue); err != nil {
+				return fmt.Errorf("Could not decode the property '%v': %v", key, err)
+			}
+		}
+		return nil
+	}
+	return decodeJsonWithNumbers(data, result)
+}
+
+// A struct field, as seen by encoding/json.
+type jsonField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var jsonFieldsCache sync.Map
+
+func jsonFieldsOf(t reflect.Type) []jsonField {
+	if fields, ok := jsonFieldsCache.Load(t); ok {
+		return fields.([]jsonField)
+	}
+	fields := collectJsonFields(t, nil)
+	jsonFieldsCache.Store(t, fields)
+	return fields
+}
+
+func collectJsonFields(t reflect.Type, parentIndex []int) []jsonField {
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		index := append(append([]int{}, parentIndex...), i)
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && parts[0] == "" && fieldType.Kind() == reflect.Struct {
+			fields = append(fields, collectJsonFields(fieldType, index)...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		name := parts[0]
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, jsonField{name, index, hasTagOption(parts[1:], "omitempty")})
+	}
+	return fields
+}
+
+func matchJsonField(fields []jsonField, key string) *jsonField {
+	var match *jsonField
+	for i := range fields {
+		if fields[i].name == key {
+			return &fields[i]
+		}
+		if match == nil && strings.EqualFold(fields[i].name, key) {
+			match = &fields[i]
+		}
+	}
+	return match
+}
+
+// Like reflect.Value.FieldByIndex, but allocates the nil embedded pointers.
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("Cannot set an embedded pointer to an unexported struct type %v.", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+func isEmptyJsonValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// A fixed-width variant of time.RFC3339Nano, for the times in UTC.
+const propertyTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+type timeCodec struct{}
+
+func (timeCodec) EncodeProperty(value interface{}) (interface{}, error) {
+	return value.(time.Time).UTC().Format(propertyTimeLayout), nil
+}
+
+func (timeCodec) DecodeProperty(property interface{}, dest interface{}) error {
+	s, ok := property.(string)
+	if !ok {
+		return fmt.Errorf("Expected a time string, but got %v.", property)
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return err
+	}
+	*dest.(*time.Time) = t
+	return nil
+}
+
+type durationCodec struct{}
+
+func (durationCodec) EncodeProperty(value interface{}) (interface{}, error) {
+	return int64(value.(time.Duration)), nil
+}
+
+func (durationCodec) DecodeProperty(property interface{}, dest interface{}) error {
+	n, ok := property.(json.Number)
+	if !ok {
+		return fmt.Errorf("Expected a number of nanoseconds, but got %v.", property)
+	}
+	nanoseconds, err := n.Int64()
+	if err != nil {
+		return err
+	}
+	*dest.(*time.Duration) = time.Duration(nanoseconds)
+	return nil
+}
+
+type ratCodec struct{}
+
+func (ratCodec) EncodeProperty(value interface{}) (interface{}, error) {
+	r := value.(big.Rat)
+	return r.RatString(), nil
+}
+
+func (ratCodec) DecodeProperty(property interface{}, dest interface{}) error {
+	var s string
+	switch p := property.(type) {
+	case string:
+		s = p
+	case json.Number:
+		s = p.String()
+	default:
+		return fmt.Errorf("Expected a rational number string, but got %v.", property)
+	}
+	if _, ok := dest.(*big.Rat).SetString(s); !ok {
+		return fmt.Errorf("Invalid rational number: %v", s)
+	}
+	return nil
+}
+
+type uuidCodec struct{}
+
+func (uuidCodec) EncodeProperty(value interface{}) (interface{}, error) {
+	var b [16]byte
+	reflect.Copy(reflect.ValueOf(b[:]), reflect.ValueOf(value))
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
+
+func (uuidCodec) DecodeProperty(property interface{}, dest interface{}) error {
+	s, ok := property.(string)
+	if !ok {
+		return fmt.Errorf("Expected a UUID string, but got %v.", property)
+	}
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != 16 {
+		return fmt.Errorf("Invalid UUID: %v", s)
+	}
+	reflect.Copy(reflect.ValueOf(dest).Elem(), reflect.ValueOf(b))
+	return nil
+}
@@ -0,0 +1,207 @@
+package neo2go
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type propertyMoney struct {
+	Currency string
+	Cents    int64
+}
+
+func (m propertyMoney) MarshalProperty() (interface{}, error) {
+	return fmt.Sprintf("%v %d", m.Currency, m.Cents), nil
+}
+
+func (m *propertyMoney) UnmarshalProperty(property interface{}) error {
+	s, ok := property.(string)
+	if !ok {
+		return errors.New("Expected a string.")
+	}
+	_, err := fmt.Sscanf(s, "%s %d", &m.Currency, &m.Cents)
+	return err
+}
+
+// Both methods have pointer receivers.
+type propertyVersion struct {
+	Major, Minor int
+}
+
+func (v *propertyVersion) MarshalProperty() (interface{}, error) {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor), nil
+}
+
+func (v *propertyVersion) UnmarshalProperty(property interface{}) error {
+	s, _ := property.(string)
+	_, err := fmt.Sscanf(s, "%d.%d", &v.Major, &v.Minor)
+	return err
+}
+
+type propertyUuid [16]byte
+
+type codecEntity struct {
+	Created  time.Time     `json:"created"`
+	Timeout  time.Duration `json:"timeout"`
+	Id       propertyUuid  `json:"id"`
+	Share    *big.Rat      `json:"share"`
+	Price    propertyMoney `json:"price"`
+	Visits   []time.Time   `json:"visits"`
+	Modified *time.Time    `json:"modified,omitempty"`
+}
+
+func TestPropertyCodecsRoundTrip(t *testing.T) {
+	created := time.Date(2014, 1, 2, 3, 4, 5, 6, time.UTC)
+	entity := codecEntity{
+		Created: created,
+		Timeout: 90 * time.Second,
+		Id:      propertyUuid{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8},
+		Share:   big.NewRat(1, 3),
+		Price:   propertyMoney{"EUR", 1250},
+		Visits:  []time.Time{created},
+	}
+
+	encoded, err := prepareProperties(&entity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(encoded)
+	expected := `{"created":"2014-01-02T03:04:05.000000006Z","id":"6ba7b810-9dad-11d1-80b4-00c04fd430c8",` +
+		`"price":"EUR 1250","share":"1/3","timeout":90000000000,"visits":["2014-01-02T03:04:05.000000006Z"]}`
+	if string(data) != expected {
+		t.Fatalf("Unexpected properties: %s", data)
+	}
+
+	var decoded codecEntity
+	node := &NeoNode{Data: data}
+	if err := node.ParseData(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Created.Equal(created) || decoded.Timeout != entity.Timeout || decoded.Id != entity.Id ||
+		decoded.Share.Cmp(entity.Share) != 0 || decoded.Price != entity.Price || len(decoded.Visits) != 1 || decoded.Modified != nil {
+		t.Errorf("Unexpected entity: %+v", decoded)
+	}
+}
+
+func TestPointerReceiverPropertyCodec(t *testing.T) {
+	type release struct {
+		Version  propertyVersion   `json:"version"`
+		Previous []propertyVersion `json:"previous"`
+	}
+	entity := release{Version: propertyVersion{2, 1}, Previous: []propertyVersion{{1, 9}, {2, 0}}}
+	expected := `{"previous":["1.9","2.0"],"version":"2.1"}`
+
+	for _, properties := range []interface{}{&entity, entity} {
+		encoded, err := prepareProperties(properties)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := json.Marshal(encoded); string(data) != expected {
+			t.Errorf("Unexpected properties of %T: %s", properties, data)
+		}
+	}
+
+	var decoded release
+	node := &NeoNode{Data: json.RawMessage(expected)}
+	if err := node.ParseData(&decoded); err != nil || !reflect.DeepEqual(decoded, entity) {
+		t.Errorf("Unexpected entity: %+v (%v)", decoded, err)
+	}
+
+	versions := map[string]propertyVersion{"current": {3, 0}}
+	encoded, err := prepareProperties(versions)
+	if data, _ := json.Marshal(encoded); err != nil || string(data) != `{"current":"3.0"}` {
+		t.Errorf("Unexpected properties: %s (%v)", data, err)
+	}
+	value, err := preparePropertyValue("version", propertyVersion{1, 2})
+	if err != nil || value != "1.2" {
+		t.Errorf("Unexpected property: %v (%v)", value, err)
+	}
+}
+
+func TestTimePropertiesSortLikeTimes(t *testing.T) {
+	warsaw := time.FixedZone("CEST", 2*60*60)
+	times := []time.Time{
+		time.Date(2014, 1, 2, 3, 4, 5, 0, time.UTC),
+		time.Date(2014, 1, 2, 3, 4, 5, 500000000, time.UTC),
+		time.Date(2014, 1, 2, 5, 4, 6, 0, warsaw),
+	}
+
+	var encoded []string
+	for _, tm := range times {
+		value, err := encodePropertyValue(tm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded = append(encoded, value.(string))
+	}
+	expected := []string{"2014-01-02T03:04:05.000000000Z", "2014-01-02T03:04:05.500000000Z", "2014-01-02T03:04:06.000000000Z"}
+	if !reflect.DeepEqual(encoded, expected) {
+		t.Fatalf("Unexpected encoded times: %v", encoded)
+	}
+
+	var decoded time.Time
+	if err := (timeCodec{}).DecodeProperty(encoded[2], &decoded); err != nil || !decoded.Equal(times[2]) {
+		t.Errorf("Expected %v, but got %v (%v)", times[2], decoded, err)
+	}
+}
+
+func TestRegisteredPropertyCodec(t *testing.T) {
+	type celsius float64
+	RegisterPropertyCodec(celsius(0), celsiusCodec{})
+	defer RegisterPropertyCodec(celsius(0), nil)
+
+	encoded, err := preparePropertyValue("temperature", celsius(21.5))
+	if err != nil || encoded != "21.5C" {
+		t.Fatalf("Unexpected property: %v (%v)", encoded, err)
+	}
+
+	var properties map[string]celsius
+	node := &NeoNode{Data: json.RawMessage(`{"temperature":"21.5C"}`)}
+	if err := node.ParseData(&properties); err != nil || properties["temperature"] != 21.5 {
+		t.Errorf("Unexpected properties: %v (%v)", properties, err)
+	}
+	if err := node.ParseData(&map[string]time.Duration{}); err == nil || !strings.Contains(err.Error(), "temperature") {
+		t.Errorf("Expected an error for the property, but got: %v", err)
+	}
+}
+
+type celsiusCodec struct{}
+
+func (celsiusCodec) EncodeProperty(value interface{}) (interface{}, error) {
+	return fmt.Sprintf("%vC", value), nil
+}
+
+func (celsiusCodec) DecodeProperty(property interface{}, dest interface{}) error {
+	s, _ := property.(string)
+	var value float64
+	if _, err := fmt.Sscanf(s, "%gC", &value); err != nil {
+		return err
+	}
+	reflect.ValueOf(dest).Elem().SetFloat(value)
+	return nil
+}
+
+func TestScanPropertyCodecs(t *testing.T) {
+	var resp CypherResponse
+	body := `{"columns": ["n.created", "n.id"], "data": [["2014-01-02T03:04:05Z", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"]]}`
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []struct {
+		Created time.Time    `neo:"n.created"`
+		Id      propertyUuid `neo:"n.id"`
+	}
+	if err := resp.ScanAll(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Created.Year() != 2014 || rows[0].Id[0] != 0x6b {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+}
@@ -0,0 +1,275 @@
+package neo2go
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The type of a property value, as stored by Neo4j (see the comment at the top of graph_structures.go).
+// Integers are stored as longs, and floating point numbers as doubles.
+type PropertyType uint8
+
+const (
+	PropertyBoolean PropertyType = iota + 1
+	PropertyLong
+	PropertyDouble
+	PropertyString
+	PropertyBooleanArray
+	PropertyLongArray
+	PropertyDoubleArray
+	PropertyStringArray
+)
+
+func (p PropertyType) String() string {
+	switch p {
+	case PropertyBoolean:
+		return "boolean"
+	case PropertyLong:
+		return "long"
+	case PropertyDouble:
+		return "double"
+	case PropertyString:
+		return "String"
+	case PropertyBooleanArray:
+		return "boolean[]"
+	case PropertyLongArray:
+		return "long[]"
+	case PropertyDoubleArray:
+		return "double[]"
+	case PropertyStringArray:
+		return "String[]"
+	}
+	return "unknown"
+}
+
+func (p PropertyType) IsArray() bool {
+	return p >= PropertyBooleanArray
+}
+
+func (p PropertyType) arrayOf() PropertyType {
+	return p + PropertyBooleanArray - PropertyBoolean
+}
+
+// Errors returned (wrapped) when a value cannot be stored as a property.
+var (
+	ErrNilProperty        = errors.New("Neo4j cannot store null property values (delete the property instead).")
+	ErrNestedProperty     = errors.New("Neo4j cannot store maps or objects as property values.")
+	ErrMixedArrayProperty = errors.New("All the values of an array property must have the same type.")
+	// Returned only when the properties are known to be new (e.g. CreateNodeWithProperties, ReplacePropertiesForNode);
+	// SetPropertyForNode and SetPropertyForRelationship send an empty array, which the server accepts
+	// if the property already holds an array.
+	ErrEmptyArrayProperty = errors.New("Neo4j can store an empty array only if the property already holds an array.")
+	ErrPropertyOutOfRange = errors.New("The property value is out of range of the Neo4j types.")
+)
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// Returns the type, as which the value would be stored, or an error if it cannot be stored.
+// The values implementing json.Marshaler (e.g. json.RawMessage or time.Time) are checked
+// as they are encoded.
+func PropertyTypeOf(value interface{}) (PropertyType, error) {
+	if value == nil {
+		return 0, ErrNilProperty
+	}
+	return propertyTypeOf(reflect.ValueOf(value))
+}
+
+func propertyTypeOf(v reflect.Value) (PropertyType, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return 0, ErrNilProperty
+		}
+		if v.Type().Implements(jsonMarshalerType) {
+			break
+		}
+		v = v.Elem()
+	}
+
+	switch n := v.Interface().(type) {
+	case json.Number:
+		return numberPropertyType(n)
+	case json.Marshaler:
+		return marshaledPropertyType(n)
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(jsonMarshalerType) {
+		return marshaledPropertyType(v.Addr().Interface().(json.Marshaler))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return PropertyBoolean, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return PropertyLong, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%w (%v does not fit in a long)", ErrPropertyOutOfRange, v.Uint())
+		}
+		return PropertyLong, nil
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, fmt.Errorf("%w (%v cannot be encoded)", ErrPropertyOutOfRange, f)
+		}
+		return PropertyDouble, nil
+	case reflect.String:
+		return PropertyString, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			// Encoded as a base64 string.
+			return PropertyString, nil
+		}
+		return arrayPropertyType(v)
+	case reflect.Map, reflect.Struct:
+		return 0, ErrNestedProperty
+	}
+	return 0, fmt.Errorf("The type %v cannot be stored as a property.", v.Type())
+}
+
+func arrayPropertyType(v reflect.Value) (PropertyType, error) {
+	if v.Len() == 0 {
+		return 0, ErrEmptyArrayProperty
+	}
+	var elemType PropertyType
+	for i := 0; i < v.Len(); i++ {
+		t, err := propertyTypeOf(v.Index(i))
+		if err != nil {
+			return 0, fmt.Errorf("Invalid array element #%d: %w", i, err)
+		}
+		if t.IsArray() {
+			return 0, fmt.Errorf("%w (nested arrays are not supported)", ErrNestedProperty)
+		}
+		if i > 0 && t != elemType {
+			return 0, fmt.Errorf("%w (%v and %v)", ErrMixedArrayProperty, elemType, t)
+		}
+		elemType = t
+	}
+	return elemType.arrayOf(), nil
+}
+
+func numberPropertyType(n json.Number) (PropertyType, error) {
+	if !strings.ContainsAny(string(n), ".eE") {
+		if _, err := strconv.ParseInt(string(n), 10, 64); err != nil {
+			return 0, fmt.Errorf("%w (%v does not fit in a long)", ErrPropertyOutOfRange, n)
+		}
+		return PropertyLong, nil
+	}
+	if _, err := strconv.ParseFloat(string(n), 64); err != nil {
+		return 0, fmt.Errorf("%w (%v does not fit in a double)", ErrPropertyOutOfRange, n)
+	}
+	return PropertyDouble, nil
+}
+
+func marshaledPropertyType(m json.Marshaler) (PropertyType, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return 0, err
+	}
+	var value interface{}
+	if err := decodeJsonWithNumbers(data, &value); err != nil {
+		return 0, err
+	}
+	return decodedPropertyType(value)
+}
+
+// Returns the type of a value decoded from JSON (with json.Number numbers).
+func decodedPropertyType(value interface{}) (PropertyType, error) {
+	if value == nil {
+		return 0, ErrNilProperty
+	}
+	values, ok := value.([]interface{})
+	if !ok || len(values) == 0 {
+		return propertyTypeOf(reflect.ValueOf(value))
+	}
+
+	// JSON does not distinguish whole doubles from longs, so arrays mixing them are stored as doubles.
+	elemType := PropertyLong
+	for i, elem := range values {
+		n, ok := elem.(json.Number)
+		if !ok {
+			return propertyTypeOf(reflect.ValueOf(value))
+		}
+		t, err := numberPropertyType(n)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid array element #%d: %w", i, err)
+		}
+		if t == PropertyDouble {
+			elemType = PropertyDouble
+		}
+	}
+	return elemType.arrayOf(), nil
+}
+
+// Checks that the value can be stored as a property.
+func ValidatePropertyValue(value interface{}) error {
+	_, err := PropertyTypeOf(value)
+	return err
+}
+
+// Validates the value of a single property, which may already exist.
+func validatePropertyForKey(key string, value interface{}) error {
+	// Only an empty array itself (not an array with an empty element) may replace an existing array.
+	if err := ValidatePropertyValue(value); err != nil && err != ErrEmptyArrayProperty {
+		return fmt.Errorf("Invalid value of the property '%v': %w", key, err)
+	}
+	return nil
+}
+
+// Checks that all the properties (a map with string keys, or a struct encoded to a JSON object)
+// can be stored. A nil value means no properties.
+func ValidateProperties(properties interface{}) error {
+	if properties == nil {
+		return nil
+	}
+	v := reflect.ValueOf(properties)
+	for v.Kind() == reflect.Ptr && !v.IsNil() && !v.Type().Implements(jsonMarshalerType) {
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String && !v.Type().Implements(jsonMarshalerType) {
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			if _, err := propertyTypeOf(v.MapIndex(key)); err != nil {
+				return fmt.Errorf("Invalid value of the property '%v': %w", key.String(), err)
+			}
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(properties)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	var decoded map[string]interface{}
+	if err := decodeJsonWithNumbers(data, &decoded); err != nil {
+		return fmt.Errorf("The properties must be encoded as a JSON object: %v", err)
+	}
+	keys := make([]string, 0, len(decoded))
+	for key := range decoded {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, err := decodedPropertyType(decoded[key]); err != nil {
+			return fmt.Errorf("Invalid value of the property '%v': %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Decodes the data like json.Unmarshal, but keeps the numbers as json.Number values
+// (instead of float64), so that longs do not lose their precision.
+func decodeJsonWithNumbers(data []byte, result interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(result)
+}
@@ -0,0 +1,132 @@
+package neo2go
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPropertyTypeOf(t *testing.T) {
+	var nilPointer *string
+	cases := []struct {
+		value    interface{}
+		expected PropertyType
+		err      error
+	}{
+		{true, PropertyBoolean, nil},
+		{int8(3), PropertyLong, nil},
+		{uint64(math.MaxInt64), PropertyLong, nil},
+		{float32(1.5), PropertyDouble, nil},
+		{"text", PropertyString, nil},
+		{[]byte("bytes"), PropertyString, nil},
+		{[]int{1, 2}, PropertyLongArray, nil},
+		{[2]string{"a", "b"}, PropertyStringArray, nil},
+		{[]interface{}{1.5, 2.0}, PropertyDoubleArray, nil},
+		{json.Number("9007199254740993"), PropertyLong, nil},
+		{json.RawMessage(`[1, 2.5]`), PropertyDoubleArray, nil},
+		{time.Date(2014, 1, 2, 3, 4, 5, 0, time.UTC), PropertyString, nil},
+		{nil, 0, ErrNilProperty},
+		{nilPointer, 0, ErrNilProperty},
+		{map[string]int{"a": 1}, 0, ErrNestedProperty},
+		{struct{ A int }{1}, 0, ErrNestedProperty},
+		{[]interface{}{1, "a"}, 0, ErrMixedArrayProperty},
+		{[]string{}, 0, ErrEmptyArrayProperty},
+		{uint64(math.MaxUint64), 0, ErrPropertyOutOfRange},
+		{math.Inf(1), 0, ErrPropertyOutOfRange},
+		{json.Number("9223372036854775808"), 0, ErrPropertyOutOfRange},
+	}
+
+	for _, c := range cases {
+		actual, err := PropertyTypeOf(c.value)
+		if c.err != nil {
+			if !errors.Is(err, c.err) {
+				t.Errorf("Expected %v for %#v, but got: %v", c.err, c.value, err)
+			}
+		} else if err != nil || actual != c.expected {
+			t.Errorf("Expected %v for %#v, but got %v (%v)", c.expected, c.value, actual, err)
+		}
+	}
+}
+
+func TestValidateProperties(t *testing.T) {
+	type person struct {
+		Name    string   `json:"name"`
+		Scores  []int    `json:"scores"`
+		Manager *string  `json:"manager"`
+		Tags    []string `json:"tags,omitempty"`
+	}
+	manager := "Bob"
+	if err := ValidateProperties(&person{Name: "Alice", Scores: []int{1}, Manager: &manager}); err != nil {
+		t.Errorf("Expected the properties to be valid, but got: %v", err)
+	}
+	if err := ValidateProperties(person{Name: "Alice", Scores: []int{1}}); !errors.Is(err, ErrNilProperty) {
+		t.Errorf("Expected %v, but got: %v", ErrNilProperty, err)
+	}
+	if err := ValidateProperties(map[string]interface{}{"nested": map[string]interface{}{}}); !errors.Is(err, ErrNestedProperty) {
+		t.Errorf("Expected %v, but got: %v", ErrNestedProperty, err)
+	}
+	if err := ValidateProperties(nil); err != nil {
+		t.Errorf("Expected no properties to be valid, but got: %v", err)
+	}
+	if err := ValidateProperties([]int{1}); err == nil {
+		t.Errorf("Expected an error for properties, which are not an object.")
+	}
+}
+
+func TestInvalidPropertiesAreNotSent(t *testing.T) {
+	server := newFakeNeoServer(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("Unexpected request: %v %v", r.Method, r.URL)
+	})
+	defer server.Close()
+
+	service := NewGraphDatabaseService()
+	checkResponseSucceeded(t, service.Connect(server.URL), 200)
+	node, _ := service.nodeFromId(1)
+
+	if resp := service.SetPropertyForNode(node, "tags", []interface{}{1, "a"}); resp.StatusCode != 600 || !errors.Is(resp.Err, ErrMixedArrayProperty) {
+		t.Errorf("Expected %v, but got: %v", ErrMixedArrayProperty, resp.Err)
+	}
+	if _, resp := service.CreateNodeWithProperties(map[string]interface{}{"name": nil}); !errors.Is(resp.Err, ErrNilProperty) {
+		t.Errorf("Expected %v, but got: %v", ErrNilProperty, resp.Err)
+	}
+
+	batch := service.Batch()
+	batch.CreateNode()
+	if resp := batch.ReplacePropertiesForNode(node, map[string]interface{}{"tags": []string{}}); !errors.Is(resp.Err, ErrEmptyArrayProperty) {
+		t.Errorf("Expected %v, but got: %v", ErrEmptyArrayProperty, resp.Err)
+	}
+	if resp := batch.Commit(); !errors.Is(resp.Err, ErrEmptyArrayProperty) {
+		t.Errorf("Expected %v, but got: %v", ErrEmptyArrayProperty, resp.Err)
+	}
+
+	// An existing array property can be emptied.
+	batch = service.Batch()
+	if resp := batch.SetPropertyForNode(node, "tags", []string{}); resp.Err != nil {
+		t.Errorf("Expected an empty array to be accepted for an existing property, but got: %v", resp.Err)
+	}
+	if resp := batch.SetPropertyForNode(node, "tags", [][]string{{}}); !errors.Is(resp.Err, ErrNestedProperty) && !errors.Is(resp.Err, ErrEmptyArrayProperty) {
+		t.Errorf("Expected an error for an array with an empty element, but got: %v", resp.Err)
+	}
+}
+
+func TestDataKeepsLongPrecision(t *testing.T) {
+	var rel NeoRelationship
+	if err := json.Unmarshal([]byte(`{"type":"KNOWS","data":{"since":9007199254740993}}`), &rel); err != nil {
+		t.Fatal(err)
+	}
+	if rel.Data["since"] != json.Number("9007199254740993") {
+		t.Errorf("Expected a json.Number, but got %#v", rel.Data["since"])
+	}
+
+	node := &NeoNode{Data: json.RawMessage(`{"id":9007199254740993}`)}
+	var data map[string]interface{}
+	if err := node.ParseData(&data); err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := data["id"].(json.Number).Int64(); id != 9007199254740993 {
+		t.Errorf("Expected the id to keep its precision, but got %v", data["id"])
+	}
+}
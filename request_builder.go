@@ -1,8 +1,10 @@
 package neo2go
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 type neoRequestBuilder struct {
@@ -18,6 +20,10 @@ type neoRequestData struct {
 	method         string
 	result         interface{}
 	requestUrl     string
+	// The request is executed in its own transaction (see RetryPolicy).
+	retrySafe bool
+	// A local error (e.g. an invalid property value), which prevents sending the request.
+	err error
 }
 
 func (n *neoRequestData) setBatchId(bid NeoBatchId) {
@@ -50,26 +56,40 @@ func (n *neoRequestBuilder) Cypher(cql string, params map[string]interface{}) (*
 
 	url := n.dataRoot.Cypher.String()
 	cypherResp := new(CypherResponse)
-	requestData := neoRequestData{body: &bodyMap, expectedStatus: 200, method: "POST", result: cypherResp, requestUrl: url}
+	requestData := neoRequestData{body: &bodyMap, expectedStatus: 200, method: "POST", result: cypherResp, requestUrl: url, retrySafe: true}
 	return cypherResp, &requestData
 }
 
+// Like Cypher, but the server also returns the profiled plan of the statement.
+func (n *neoRequestBuilder) ProfiledCypher(cql string, params map[string]interface{}) (*CypherResponse, *neoRequestData) {
+	cypherResp, requestData := n.Cypher(cql, params)
+	requestData.requestUrl += "?profile=true"
+	return cypherResp, requestData
+}
+
 // Transactional Cypher
 
 func (n *neoRequestBuilder) TransactionalCypher(cypherTrans *CypherTransaction, commit bool, requests ...*CypherTransactionRequest) (*CypherTransaction, *neoRequestData) {
 	statememts := make([]map[string]interface{}, 0, len(requests))
 	for _, req := range requests {
+		contents := req.ResultDataContents
+		if len(contents) == 0 {
+			contents = []CypherResultDataContent{CypherRestContent}
+		}
 		stmt := map[string]interface{}{
 			"statement":          req.Cql,
 			"parameters":         req.Params,
-			"resultDataContents": []string{"REST"},
+			"resultDataContents": contents,
+		}
+		if req.IncludeStats {
+			stmt["includeStats"] = true
 		}
 		statememts = append(statememts, stmt)
 	}
 
 	var url string
 	var expectedStatus int = 200
-	returnedCypherTrans := new(CypherTransaction)
+	returnedCypherTrans := &CypherTransaction{statementCount: len(requests)}
 
 	if cypherTrans != nil && commit {
 		url = cypherTrans.Commit.String()
@@ -89,10 +109,14 @@ func (n *neoRequestBuilder) TransactionalCypher(cypherTrans *CypherTransaction,
 		"statements": statememts,
 	}
 
-	requestData := neoRequestData{body: &bodyMap, expectedStatus: expectedStatus, method: "POST", result: returnedCypherTrans, requestUrl: url}
+	requestData := neoRequestData{body: &bodyMap, expectedStatus: expectedStatus, method: "POST", result: returnedCypherTrans, requestUrl: url, retrySafe: cypherTrans == nil && commit}
 	return returnedCypherTrans, &requestData
 }
 
+func (n *neoRequestBuilder) RollbackCypherTransaction(cypherTrans *CypherTransaction) *neoRequestData {
+	return &neoRequestData{expectedStatus: 200, method: "DELETE", requestUrl: cypherTrans.Self.String()}
+}
+
 // Grapher
 
 func (n *neoRequestBuilder) CreateNode() (*NeoNode, *neoRequestData) {
@@ -103,6 +127,7 @@ func (n *neoRequestBuilder) CreateNodeWithProperties(properties interface{}) (*N
 	node := new(NeoNode)
 	url := n.dataRoot.Node.String()
 	requestData := neoRequestData{body: properties, expectedStatus: 201, method: "POST", result: node, requestUrl: url}
+	requestData.body, requestData.err = prepareProperties(properties)
 	return node, &requestData
 }
 
@@ -118,6 +143,52 @@ func (n *neoRequestBuilder) AddLabels(node *NeoNode, labels []string) *neoReques
 	return &neoRequestData{body: labels, expectedStatus: 204, method: "POST", requestUrl: node.Labels.String()}
 }
 
+func (n *neoRequestBuilder) ReplaceLabels(node *NeoNode, labels []string) *neoRequestData {
+	return &neoRequestData{body: labels, expectedStatus: 204, method: "PUT", requestUrl: node.Labels.String()}
+}
+
+func (n *neoRequestBuilder) RemoveLabel(node *NeoNode, label string) *neoRequestData {
+	requestUrl := node.Labels.String() + "/" + url.PathEscape(label)
+	return &neoRequestData{expectedStatus: 204, method: "DELETE", requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) GetLabelsForNode(node *NeoNode) (*[]string, *neoRequestData) {
+	var labels []string
+	requestData := neoRequestData{expectedStatus: 200, method: "GET", result: &labels, requestUrl: node.Labels.String()}
+	return &labels, &requestData
+}
+
+// The url of the nodes with the given label, e.g. http://localhost:7474/db/data/label/Person/nodes
+// (the node_labels url of the data root is http://localhost:7474/db/data/labels).
+func (n *neoRequestBuilder) nodesWithLabelUrl(label string) string {
+	return strings.TrimSuffix(n.dataRoot.NodeLabels.String(), "s") + "/" + url.PathEscape(label) + "/nodes"
+}
+
+func (n *neoRequestBuilder) GetNodesWithLabel(label string) (*[]*NeoNode, *neoRequestData) {
+	var nodes []*NeoNode
+	requestData := neoRequestData{expectedStatus: 200, method: "GET", result: &nodes, requestUrl: n.nodesWithLabelUrl(label)}
+	return &nodes, &requestData
+}
+
+func (n *neoRequestBuilder) GetNodesWithLabelAndProperty(label string, propertyKey string, propertyValue interface{}) (*[]*NeoNode, *neoRequestData, error) {
+	var nodes []*NeoNode
+	// The property value has to be JSON encoded, e.g. ?name=%22Clint+Eastwood%22
+	value, err := json.Marshal(propertyValue)
+	if err != nil {
+		return &nodes, nil, err
+	}
+	query := url.Values{propertyKey: []string{string(value)}}
+	requestUrl := n.nodesWithLabelUrl(label) + "?" + query.Encode()
+	requestData := neoRequestData{expectedStatus: 200, method: "GET", result: &nodes, requestUrl: requestUrl}
+	return &nodes, &requestData, nil
+}
+
+func (n *neoRequestBuilder) GetAllLabels() (*[]string, *neoRequestData) {
+	var labels []string
+	requestData := neoRequestData{expectedStatus: 200, method: "GET", result: &labels, requestUrl: n.dataRoot.NodeLabels.String()}
+	return &labels, &requestData
+}
+
 func (n *neoRequestBuilder) GetNode(nodeUrl string) (*NeoNode, *neoRequestData) {
 	node := new(NeoNode)
 	requestData := neoRequestData{expectedStatus: 200, method: "GET", result: node, requestUrl: nodeUrl}
@@ -151,7 +222,9 @@ func (n *neoRequestBuilder) CreateRelationshipWithPropertiesAndType(source *NeoN
 		"type": relType,
 		"data": properties,
 	}
-	return n.createRelationshipHelper(source, bodyMap)
+	relationship, requestData := n.createRelationshipHelper(source, bodyMap)
+	bodyMap["data"], requestData.err = prepareProperties(properties)
+	return relationship, requestData
 }
 
 func (n *neoRequestBuilder) DeleteRelationship(rel *NeoRelationship) *neoRequestData {
@@ -167,6 +240,7 @@ func (n *neoRequestBuilder) GetPropertiesForRelationship(rel *NeoRelationship) *
 func (n *neoRequestBuilder) ReplacePropertiesForRelationship(rel *NeoRelationship, properties interface{}) *neoRequestData {
 	url := rel.Properties.String()
 	requestData := neoRequestData{body: properties, expectedStatus: 204, method: "PUT", requestUrl: url}
+	requestData.body, requestData.err = prepareProperties(properties)
 	return &requestData
 }
 
@@ -185,6 +259,7 @@ func (n *neoRequestBuilder) SetPropertyForRelationship(rel *NeoRelationship, pro
 		return nil, err
 	}
 	requestData := neoRequestData{body: propertyValue, expectedStatus: 204, method: "PUT", requestUrl: url}
+	requestData.body, requestData.err = preparePropertyValue(propertyKey, propertyValue)
 	return &requestData, nil
 }
 
@@ -255,12 +330,15 @@ func (n *neoRequestBuilder) SetPropertyForNode(node *NeoNode, propertyKey string
 		return nil, err
 	}
 	requestData := neoRequestData{body: propertyValue, expectedStatus: 204, method: "PUT", requestUrl: url}
+	requestData.body, requestData.err = preparePropertyValue(propertyKey, propertyValue)
 	return &requestData, nil
 }
 
 func (n *neoRequestBuilder) ReplacePropertiesForNode(node *NeoNode, properties interface{}) *neoRequestData {
 	url := node.Properties.String()
-	return &neoRequestData{body: properties, expectedStatus: 204, method: "PUT", requestUrl: url}
+	requestData := neoRequestData{body: properties, expectedStatus: 204, method: "PUT", requestUrl: url}
+	requestData.body, requestData.err = prepareProperties(properties)
+	return &requestData
 }
 
 func (n *neoRequestBuilder) GetPropertyForNode(node *NeoNode, propertyKey string) (*neoRequestData, error) {
@@ -490,7 +568,11 @@ func (n *neoRequestBuilder) GetOrCreateUniqueNodeWithProperties(index *NeoIndex,
 		"value":      value,
 		"properties": properties,
 	}
-	return getOrCreateUniqueNodeHelper(index, params)
+	node, requestData, err := getOrCreateUniqueNodeHelper(index, params)
+	if requestData != nil {
+		params["properties"], requestData.err = prepareProperties(properties)
+	}
+	return node, requestData, err
 }
 
 func createUniqueNodeOrFailHelper(index *NeoIndex, params map[string]interface{}) (*NeoNode, *neoRequestData, error) {
@@ -517,7 +599,11 @@ func (n *neoRequestBuilder) CreateUniqueNodeWithPropertiesOrFail(index *NeoIndex
 		"value":      value,
 		"properties": properties,
 	}
-	return createUniqueNodeOrFailHelper(index, params)
+	node, requestData, err := createUniqueNodeOrFailHelper(index, params)
+	if requestData != nil {
+		params["properties"], requestData.err = prepareProperties(properties)
+	}
+	return node, requestData, err
 }
 
 func getOrCreateUniqueRelationshipHelper(index *NeoIndex, params map[string]interface{}) (*NeoRelationship, *neoRequestData, error) {
@@ -550,7 +636,11 @@ func (n *neoRequestBuilder) GetOrCreateUniqueRelationshipWithProperties(index *N
 		"data":  properties,
 		"type":  relType,
 	}
-	return getOrCreateUniqueRelationshipHelper(index, params)
+	relationship, requestData, err := getOrCreateUniqueRelationshipHelper(index, params)
+	if requestData != nil {
+		params["data"], requestData.err = prepareProperties(properties)
+	}
+	return relationship, requestData, err
 }
 
 func createUniqueRelationshipOrFailHelper(index *NeoIndex, params map[string]interface{}) (*NeoRelationship, *neoRequestData, error) {
@@ -583,7 +673,105 @@ func (n *neoRequestBuilder) CreateUniqueRelationshipWithPropertiesOrFail(index *
 		"data":  properties,
 		"type":  relType,
 	}
-	return createUniqueRelationshipOrFailHelper(index, params)
+	relationship, requestData, err := createUniqueRelationshipOrFailHelper(index, params)
+	if requestData != nil {
+		params["data"], requestData.err = prepareProperties(properties)
+	}
+	return relationship, requestData, err
+}
+
+// Auto-indexes
+
+// The url of the auto-index, e.g. http://localhost:7474/db/data/index/auto/node
+// (the node_index url of the data root is http://localhost:7474/db/data/index/node).
+func autoIndexUrl(indexUrl *UrlTemplate, kind string) string {
+	return strings.TrimSuffix(indexUrl.String(), kind) + "auto/" + kind
+}
+
+func (n *neoRequestBuilder) FindNodeByExactMatchingAutoIndex(key, value string) (*[]*NeoNode, *neoRequestData) {
+	var result []*NeoNode
+	requestUrl := autoIndexUrl(n.dataRoot.NodeIndex, "node") + "/" + url.PathEscape(key) + "/" + url.PathEscape(value)
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) FindNodeByQueryingAutoIndex(query string) (*[]*NeoNode, *neoRequestData) {
+	var result []*NeoNode
+	requestUrl := autoIndexUrl(n.dataRoot.NodeIndex, "node") + "/?query=" + url.QueryEscape(query)
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) CreateNodeAutoIndexWithConfiguration(config interface{}) (*NeoIndex, *neoRequestData) {
+	return n.CreateNodeIndexWithConfiguration("node_auto_index", config)
+}
+
+func (n *neoRequestBuilder) GetNodeAutoIndexStatus() (*bool, *neoRequestData) {
+	var result bool
+	requestUrl := autoIndexUrl(n.dataRoot.NodeIndex, "node") + "/status"
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) SetNodeAutoIndexStatus(enabled bool) *neoRequestData {
+	requestUrl := autoIndexUrl(n.dataRoot.NodeIndex, "node") + "/status"
+	return &neoRequestData{body: enabled, expectedStatus: 204, method: "PUT", requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) GetNodeAutoIndexProperties() (*[]string, *neoRequestData) {
+	var result []string
+	requestUrl := autoIndexUrl(n.dataRoot.NodeIndex, "node") + "/properties"
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) AddNodeAutoIndexProperty(propertyName string) *neoRequestData {
+	requestUrl := autoIndexUrl(n.dataRoot.NodeIndex, "node") + "/properties"
+	return &neoRequestData{body: propertyName, expectedStatus: 204, method: "POST", requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) DeleteNodeAutoIndexProperty(propertyName string) *neoRequestData {
+	requestUrl := autoIndexUrl(n.dataRoot.NodeIndex, "node") + "/properties/" + url.PathEscape(propertyName)
+	return &neoRequestData{expectedStatus: 204, method: "DELETE", requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) FindRelationshipByExactMatchingAutoIndex(key, value string) (*[]*NeoRelationship, *neoRequestData) {
+	var result []*NeoRelationship
+	requestUrl := autoIndexUrl(n.dataRoot.RelationshipIndex, "relationship") + "/" + url.PathEscape(key) + "/" + url.PathEscape(value)
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) FindRelationshipByQueryingAutoIndex(query string) (*[]*NeoRelationship, *neoRequestData) {
+	var result []*NeoRelationship
+	requestUrl := autoIndexUrl(n.dataRoot.RelationshipIndex, "relationship") + "/?query=" + url.QueryEscape(query)
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) CreateRelationshipAutoIndexWithConfiguration(config interface{}) (*NeoIndex, *neoRequestData) {
+	return n.CreateRelationshipIndexWithConfiguration("relationship_auto_index", config)
+}
+
+func (n *neoRequestBuilder) GetRelationshipAutoIndexStatus() (*bool, *neoRequestData) {
+	var result bool
+	requestUrl := autoIndexUrl(n.dataRoot.RelationshipIndex, "relationship") + "/status"
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) SetRelationshipAutoIndexStatus(enabled bool) *neoRequestData {
+	requestUrl := autoIndexUrl(n.dataRoot.RelationshipIndex, "relationship") + "/status"
+	return &neoRequestData{body: enabled, expectedStatus: 204, method: "PUT", requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) GetRelationshipAutoIndexProperties() (*[]string, *neoRequestData) {
+	var result []string
+	requestUrl := autoIndexUrl(n.dataRoot.RelationshipIndex, "relationship") + "/properties"
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) AddRelationshipAutoIndexProperty(propertyName string) *neoRequestData {
+	requestUrl := autoIndexUrl(n.dataRoot.RelationshipIndex, "relationship") + "/properties"
+	return &neoRequestData{body: propertyName, expectedStatus: 204, method: "POST", requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) DeleteRelationshipAutoIndexProperty(propertyName string) *neoRequestData {
+	requestUrl := autoIndexUrl(n.dataRoot.RelationshipIndex, "relationship") + "/properties/" + url.PathEscape(propertyName)
+	return &neoRequestData{expectedStatus: 204, method: "DELETE", requestUrl: requestUrl}
 }
 
 // GraphTraverser
@@ -678,6 +866,68 @@ func (n *neoRequestBuilder) TraverseByFullPathsGetNextPage(traverser *NeoPagedTr
 	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: traverser.location}
 }
 
+// GraphSchemaManager
+
+func (n *neoRequestBuilder) schemaIndexUrl(label string) string {
+	return n.dataRoot.Indexes.String() + "/" + url.PathEscape(label)
+}
+
+func (n *neoRequestBuilder) uniquenessConstraintUrl(label string) string {
+	return n.dataRoot.Constraints.String() + "/" + url.PathEscape(label) + "/uniqueness"
+}
+
+func (n *neoRequestBuilder) CreateSchemaIndex(label string, propertyKey string) (*SchemaIndex, *neoRequestData) {
+	result := new(SchemaIndex)
+	bodyMap := map[string]interface{}{
+		"property_keys": []string{propertyKey},
+	}
+	return result, &neoRequestData{body: bodyMap, expectedStatus: 200, method: "POST", result: result, requestUrl: n.schemaIndexUrl(label)}
+}
+
+func (n *neoRequestBuilder) GetSchemaIndexes(label string) (*[]*SchemaIndex, *neoRequestData) {
+	var result []*SchemaIndex
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: n.schemaIndexUrl(label)}
+}
+
+func (n *neoRequestBuilder) GetAllSchemaIndexes() (*[]*SchemaIndex, *neoRequestData) {
+	var result []*SchemaIndex
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: n.dataRoot.Indexes.String()}
+}
+
+func (n *neoRequestBuilder) DropSchemaIndex(label string, propertyKey string) *neoRequestData {
+	requestUrl := n.schemaIndexUrl(label) + "/" + url.PathEscape(propertyKey)
+	return &neoRequestData{expectedStatus: 204, method: "DELETE", requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) CreateUniquenessConstraint(label string, propertyKey string) (*SchemaConstraint, *neoRequestData) {
+	result := new(SchemaConstraint)
+	bodyMap := map[string]interface{}{
+		"property_keys": []string{propertyKey},
+	}
+	return result, &neoRequestData{body: bodyMap, expectedStatus: 200, method: "POST", result: result, requestUrl: n.uniquenessConstraintUrl(label)}
+}
+
+func (n *neoRequestBuilder) GetUniquenessConstraints(label string) (*[]*SchemaConstraint, *neoRequestData) {
+	var result []*SchemaConstraint
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: n.uniquenessConstraintUrl(label)}
+}
+
+func (n *neoRequestBuilder) GetConstraints(label string) (*[]*SchemaConstraint, *neoRequestData) {
+	var result []*SchemaConstraint
+	requestUrl := n.dataRoot.Constraints.String() + "/" + url.PathEscape(label)
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: requestUrl}
+}
+
+func (n *neoRequestBuilder) GetAllConstraints() (*[]*SchemaConstraint, *neoRequestData) {
+	var result []*SchemaConstraint
+	return &result, &neoRequestData{expectedStatus: 200, method: "GET", result: &result, requestUrl: n.dataRoot.Constraints.String()}
+}
+
+func (n *neoRequestBuilder) DropUniquenessConstraint(label string, propertyKey string) *neoRequestData {
+	requestUrl := n.uniquenessConstraintUrl(label) + "/" + url.PathEscape(propertyKey)
+	return &neoRequestData{expectedStatus: 204, method: "DELETE", requestUrl: requestUrl}
+}
+
 // GraphPathFinder
 
 func (n *neoRequestBuilder) FindPathFromNode(start *NeoNode, target *NeoNode, spec *NeoPathFinderSpec) (*NeoPath, *neoRequestData) {
@@ -0,0 +1,412 @@
+package neo2go
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type neoStreamKind uint8
+
+const (
+	// {"columns": [...], "data": [[...], ...]}
+	neoStreamCypher neoStreamKind = iota
+	// {"commit": "...", "results": [{"columns": [...], "data": [{"rest": [...], "row": [...]}, ...]}, ...], "errors": [...]}
+	neoStreamTransactional
+	// [{...}, ...]
+	neoStreamArray
+)
+
+type neoStreamFrame struct {
+	name   string
+	object bool
+}
+
+// Reads the results of a request incrementally, as they are received from the server,
+// so that large results can be processed without keeping them in memory.
+//
+// The stream must be closed, unless all the rows have been read (Next returned false).
+//
+//	stream, resp := service.CypherStream("MATCH (n) RETURN n", nil)
+//	if !resp.Ok() { ... }
+//	defer stream.Close()
+//	for stream.Next() {
+//		var node NeoNode
+//		err := stream.Scan(&node)
+//		...
+//	}
+//	if err := stream.Err(); err != nil { ... }
+type NeoResultStream struct {
+	kind        neoStreamKind
+	body        io.ReadCloser
+	cancel      context.CancelFunc
+	dec         *json.Decoder
+	stack       []neoStreamFrame
+	started     bool
+	inRows      bool
+	columns     []string
+	resultIndex int
+	// The number of statements sent in a transactional request.
+	statementCount int
+	current        json.RawMessage
+	row            []json.RawMessage
+	errors         []NeoError
+	notifications  []CypherNotification
+	commit         *UrlTemplate
+	err            error
+	closed         bool
+	// Only set for the statements executed in an open transaction (see ExecuteCypherStream).
+	self *UrlTemplate
+	info *CypherTransactionInfo
+}
+
+func newNeoResultStream(kind neoStreamKind, body io.ReadCloser, cancel context.CancelFunc) *NeoResultStream {
+	return &NeoResultStream{kind: kind, body: body, cancel: cancel, dec: json.NewDecoder(body), resultIndex: -1}
+}
+
+// Advances to the next row (or the next element, for traversals).
+// Returns false, when there are no more rows or an error has occurred (see Err).
+func (n *NeoResultStream) Next() bool {
+	if n.err != nil || n.closed {
+		return false
+	}
+	n.current = nil
+	n.row = nil
+
+	for {
+		if n.inRows {
+			if n.dec.More() {
+				if err := n.dec.Decode(&n.current); err != nil {
+					return n.fail(err)
+				}
+				return true
+			}
+			if err := n.expectDelim(']'); err != nil {
+				return n.fail(err)
+			}
+			n.inRows = false
+			n.pop()
+			continue
+		}
+
+		found, err := n.advance()
+		if err != nil {
+			return n.fail(err)
+		}
+		if !found {
+			n.Close()
+			return false
+		}
+	}
+}
+
+// Returns the columns of the current result (not available for traversals).
+func (n *NeoResultStream) Columns() []string {
+	return n.columns
+}
+
+// Returns the index of the current result; for transactional Cypher, each statement has its own result.
+func (n *NeoResultStream) ResultIndex() int {
+	if n.resultIndex < 0 {
+		return 0
+	}
+	return n.resultIndex
+}
+
+// Returns the values of the current row. For traversals, the row contains a single element.
+func (n *NeoResultStream) Row() []json.RawMessage {
+	if n.current == nil {
+		return nil
+	}
+	if n.row == nil {
+		switch n.kind {
+		case neoStreamCypher:
+			if err := json.Unmarshal(n.current, &n.row); err != nil {
+				n.err = err
+			}
+		case neoStreamTransactional:
+			var row CypherRow
+			if err := json.Unmarshal(n.current, &row); err != nil {
+				n.err = err
+			}
+			n.row = row.Values()
+		default:
+			n.row = []json.RawMessage{n.current}
+		}
+	}
+	return n.row
+}
+
+// Decodes the current row (or element, for traversals) into v.
+func (n *NeoResultStream) Decode(v interface{}) error {
+	if n.current == nil {
+		return fmt.Errorf("Decode called without a successful call to Next.")
+	}
+	return json.Unmarshal(n.current, v)
+}
+
+// Scans the current row into dest (see CypherRows).
+func (n *NeoResultStream) Scan(dest ...interface{}) error {
+	row := n.Row()
+	if row == nil {
+		return fmt.Errorf("Scan called without a successful call to Next.")
+	}
+	return ScanCypherRow(n.columns, row, dest...)
+}
+
+// Returns the error which has occurred while reading the stream, or the errors reported by the
+// server at the end of a transactional response.
+func (n *NeoResultStream) Err() error {
+	if n.err != nil {
+		return n.err
+	}
+	if len(n.errors) > 0 {
+		return newCypherTransactionError(n.errors, n.resultIndex+1, n.statementCount)
+	}
+	return nil
+}
+
+// Returns the commit URL of the transaction (only for transactional Cypher), once it has been read.
+func (n *NeoResultStream) Commit() *UrlTemplate {
+	return n.commit
+}
+
+// Returns the open transaction, with its commit URL and expiration time (only for ExecuteCypherStream),
+// once the whole response has been read. It can be passed to ExecuteCypher, CommitCypher or RollbackCypher.
+func (n *NeoResultStream) Transaction() *CypherTransaction {
+	if n.self == nil {
+		return nil
+	}
+	return &CypherTransaction{Self: n.self, Commit: n.commit, Info: n.info, statementCount: n.statementCount}
+}
+
+// Returns the notifications reported by the server (only for transactional Cypher), once they have been read.
+func (n *NeoResultStream) Notifications() []CypherNotification {
+	return n.notifications
+}
+
+// Closes the response body. It is safe to call Close multiple times.
+func (n *NeoResultStream) Close() error {
+	if n.closed {
+		return nil
+	}
+	n.closed = true
+	err := n.body.Close()
+	n.cancel()
+	return err
+}
+
+func (n *NeoResultStream) fail(err error) bool {
+	n.err = err
+	n.Close()
+	return false
+}
+
+func (n *NeoResultStream) push(name string, object bool) {
+	n.stack = append(n.stack, neoStreamFrame{name, object})
+}
+
+func (n *NeoResultStream) pop() {
+	n.stack = n.stack[:len(n.stack)-1]
+}
+
+func (n *NeoResultStream) expectDelim(delim json.Delim) error {
+	tok, err := n.dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("Unexpected token in the response: expected '%v', but got '%v'.", delim, tok)
+	}
+	return nil
+}
+
+func (n *NeoResultStream) isRowsKey(frame neoStreamFrame, key string) bool {
+	if key != "data" {
+		return false
+	}
+	return (n.kind == neoStreamCypher && frame.name == "root") || (n.kind == neoStreamTransactional && frame.name == "result")
+}
+
+// Reads the response until the beginning of the next array of rows.
+// Returns false, if the end of the response has been reached.
+func (n *NeoResultStream) advance() (bool, error) {
+	if !n.started {
+		n.started = true
+		if n.kind == neoStreamArray {
+			if err := n.expectDelim('['); err != nil {
+				return false, err
+			}
+			n.push("rows", false)
+			n.inRows = true
+			return true, nil
+		}
+		if err := n.expectDelim('{'); err != nil {
+			return false, err
+		}
+		n.push("root", true)
+	}
+
+	for len(n.stack) > 0 {
+		frame := n.stack[len(n.stack)-1]
+
+		if !frame.object {
+			// The array of transactional results.
+			if n.dec.More() {
+				if err := n.expectDelim('{'); err != nil {
+					return false, err
+				}
+				n.columns = nil
+				n.resultIndex += 1
+				n.push("result", true)
+			} else {
+				if err := n.expectDelim(']'); err != nil {
+					return false, err
+				}
+				n.pop()
+			}
+			continue
+		}
+
+		tok, err := n.dec.Token()
+		if err != nil {
+			return false, err
+		}
+		if d, ok := tok.(json.Delim); ok && d == '}' {
+			n.pop()
+			continue
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return false, fmt.Errorf("Unexpected token in the response: %v", tok)
+		}
+
+		switch {
+		case n.isRowsKey(frame, key):
+			if err := n.expectDelim('['); err != nil {
+				return false, err
+			}
+			n.push("rows", false)
+			n.inRows = true
+			return true, nil
+		case key == "columns":
+			err = n.dec.Decode(&n.columns)
+		case n.kind == neoStreamTransactional && frame.name == "root" && key == "results":
+			if err = n.expectDelim('['); err == nil {
+				n.push("results", false)
+			}
+		case n.kind == neoStreamTransactional && frame.name == "root" && key == "errors":
+			err = n.dec.Decode(&n.errors)
+		case n.kind == neoStreamTransactional && frame.name == "root" && key == "notifications":
+			err = n.dec.Decode(&n.notifications)
+		case n.kind == neoStreamTransactional && frame.name == "root" && key == "commit":
+			n.commit = new(UrlTemplate)
+			err = n.dec.Decode(n.commit)
+		case n.kind == neoStreamTransactional && frame.name == "root" && key == "transaction":
+			err = n.dec.Decode(&n.info)
+		default:
+			var skipped json.RawMessage
+			err = n.dec.Decode(&skipped)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// Streaming
+
+func (g *GraphDatabaseService) CypherStream(cql string, params map[string]interface{}) (*NeoResultStream, *NeoResponse) {
+	_, reqData := g.builder.Cypher(cql, params)
+	return g.streamFromRequestData(reqData, neoStreamCypher)
+}
+
+func (g *GraphDatabaseService) CypherAutoCommitStream(requests ...*CypherTransactionRequest) (*NeoResultStream, *NeoResponse) {
+	_, reqData := g.builder.TransactionalCypher(nil, true, requests...)
+	return g.transactionalStreamFromRequestData(reqData, len(requests))
+}
+
+// Executes the statements in the open transaction, and streams their results. The transaction stays open;
+// once the stream has been read, Transaction returns it with the new expiration time.
+func (g *GraphDatabaseService) ExecuteCypherStream(cypherTrans *CypherTransaction, requests ...*CypherTransactionRequest) (*NeoResultStream, *NeoResponse) {
+	_, reqData := g.builder.TransactionalCypher(cypherTrans, false, requests...)
+	stream, resp := g.transactionalStreamFromRequestData(reqData, len(requests))
+	if stream != nil {
+		stream.self = cypherTrans.Self
+	}
+	return stream, resp
+}
+
+func (g *GraphDatabaseService) CommitCypherStream(cypherTrans *CypherTransaction, requests ...*CypherTransactionRequest) (*NeoResultStream, *NeoResponse) {
+	_, reqData := g.builder.TransactionalCypher(cypherTrans, true, requests...)
+	return g.transactionalStreamFromRequestData(reqData, len(requests))
+}
+
+func (g *GraphDatabaseService) transactionalStreamFromRequestData(reqData *neoRequestData, statementCount int) (*NeoResultStream, *NeoResponse) {
+	stream, resp := g.streamFromRequestData(reqData, neoStreamTransactional)
+	if stream != nil {
+		stream.statementCount = statementCount
+	}
+	return stream, resp
+}
+
+func (g *GraphDatabaseService) TraverseByNodesStream(traversal *NeoTraversal, start *NeoNode) (*NeoResultStream, *NeoResponse) {
+	_, reqData, err := g.builder.TraverseByNodes(traversal, start)
+	if err != nil {
+		return nil, NewLocalErrorResponse(200, err)
+	}
+	return g.streamFromRequestData(reqData, neoStreamArray)
+}
+
+func (g *GraphDatabaseService) TraverseByRelationshipsStream(traversal *NeoTraversal, start *NeoNode) (*NeoResultStream, *NeoResponse) {
+	_, reqData, err := g.builder.TraverseByRelationships(traversal, start)
+	if err != nil {
+		return nil, NewLocalErrorResponse(200, err)
+	}
+	return g.streamFromRequestData(reqData, neoStreamArray)
+}
+
+func (g *GraphDatabaseService) TraverseByPathsStream(traversal *NeoTraversal, start *NeoNode) (*NeoResultStream, *NeoResponse) {
+	_, reqData, err := g.builder.TraverseByPaths(traversal, start)
+	if err != nil {
+		return nil, NewLocalErrorResponse(200, err)
+	}
+	return g.streamFromRequestData(reqData, neoStreamArray)
+}
+
+func (g *GraphDatabaseService) TraverseByFullPathsStream(traversal *NeoTraversal, start *NeoNode) (*NeoResultStream, *NeoResponse) {
+	_, reqData, err := g.builder.TraverseByFullPaths(traversal, start)
+	if err != nil {
+		return nil, NewLocalErrorResponse(200, err)
+	}
+	return g.streamFromRequestData(reqData, neoStreamArray)
+}
+
+// Sends the request, and returns a stream reading the response body.
+// The request is retried (according to the retry policy) only if it fails before any row is read.
+func (g *GraphDatabaseService) streamFromRequestData(reqData *neoRequestData, kind neoStreamKind) (*NeoResultStream, *NeoResponse) {
+	var stream *NeoResultStream
+	neoRequest, err := g.httpRequestFromData(reqData)
+	neoResponse := g.executeWithRetry(neoRequest, err, reqData.expectedStatus, true, func() *NeoResponse {
+		resp, cancel, neoResponse := g.roundTrip(neoRequest, reqData.expectedStatus)
+		if resp == nil {
+			return neoResponse
+		}
+		if resp.StatusCode >= 400 {
+			defer cancel()
+			defer resp.Body.Close()
+			return decodeResponse(resp, neoResponse, nil)
+		}
+		if err := checkJsonContentType(resp); err != nil {
+			resp.Body.Close()
+			cancel()
+			return NewLocalErrorResponse(reqData.expectedStatus, err)
+		}
+		stream = newNeoResultStream(kind, resp.Body, cancel)
+		return neoResponse
+	})
+	return stream, neoResponse
+}
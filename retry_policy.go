@@ -0,0 +1,123 @@
+package neo2go
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"syscall"
+	"time"
+)
+
+// Describes how requests failing with a transient error are retried.
+//
+// A request is retried when the connection to the server was refused (the request was never sent),
+// or when the server has responded with a Neo.TransientError.* status code. In the latter case,
+// only idempotent requests (GET, PUT, DELETE etc.) are retried, unless the request is known to be
+// executed in its own transaction (the legacy Cypher endpoint, auto-committed transactional Cypher
+// and batches), or RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// The maximum number of times a request is executed (including the first attempt).
+	MaxAttempts int
+	// The delay before the first retry.
+	InitialBackoff time.Duration
+	// The upper limit for the delay between retries. Zero means no limit.
+	MaxBackoff time.Duration
+	// The factor by which the delay grows after each retry.
+	Multiplier float64
+	// A fraction (0 - 1) of the delay, by which the delay is randomly increased or decreased.
+	Jitter float64
+	// If true, all requests failing with a transient error are retried, regardless of the HTTP method.
+	RetryNonIdempotent bool
+}
+
+// Returns a policy which executes a request at most 3 times, with exponentially growing delays
+// (starting from 100ms).
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// Enables retrying of the requests failing with transient errors.
+func WithRetryPolicy(policy *RetryPolicy) ServiceOption {
+	return func(c *serviceConfig) {
+		c.retryPolicy = policy
+	}
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+func isConnectionRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+func (r *RetryPolicy) canRetry(attempt int, resp *NeoResponse) bool {
+	if r == nil || attempt >= r.MaxAttempts {
+		return false
+	}
+	return isConnectionRefused(resp.Err) || resp.IsTransient()
+}
+
+func (r *RetryPolicy) shouldRetryRequest(attempt int, req *NeoHttpRequest, resp *NeoResponse) bool {
+	if !r.canRetry(attempt, resp) {
+		return false
+	}
+	if isConnectionRefused(resp.Err) {
+		return true
+	}
+	return r.RetryNonIdempotent || req.retrySafe || isIdempotentMethod(req.Method)
+}
+
+func (r *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := r.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(r.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if r.MaxBackoff > 0 && delay > float64(r.MaxBackoff) {
+		delay = float64(r.MaxBackoff)
+	}
+	if r.Jitter > 0 {
+		delay += delay * r.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// Sleeps before the next attempt. Returns early with an error, if the context is done.
+func (r *RetryPolicy) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(r.backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Executes the given unit of work (usually a whole Cypher transaction: begin, execute and commit),
+// and executes it again according to the retry policy of the service, as long as it fails with
+// a transient error. Since the server rolls back a transaction failing with a transient error,
+// the unit is retried regardless of the HTTP methods it uses.
+func (g *GraphDatabaseService) RetryCypherTransaction(unit func() *NeoResponse) *NeoResponse {
+	for attempt := 1; ; attempt++ {
+		resp := unit()
+		if !g.retryPolicy.canRetry(attempt, resp) {
+			return resp
+		}
+		if err := g.retryPolicy.wait(g.Context(), attempt); err != nil {
+			return NewLocalErrorResponse(resp.ExpectedCode, err)
+		}
+	}
+}
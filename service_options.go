@@ -0,0 +1,116 @@
+package neo2go
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Configures a GraphDatabaseService created by NewGraphDatabaseServiceWithOptions.
+type ServiceOption func(*serviceConfig)
+
+type serviceConfig struct {
+	client              *http.Client
+	transport           http.RoundTripper
+	tlsConfig           *tls.Config
+	proxy               func(*http.Request) (*url.URL, error)
+	maxConn             uint
+	requestTimeout      time.Duration
+	headers             http.Header
+	basicAuth           string
+	retryPolicy         *RetryPolicy
+	notificationHandler CypherNotificationHandler
+}
+
+// Uses the given client for all requests. The client is copied, so later changes to it
+// are not visible to the service.
+func WithHttpClient(client *http.Client) ServiceOption {
+	return func(c *serviceConfig) {
+		c.client = client
+	}
+}
+
+// Uses the given RoundTripper (e.g. for tracing or metrics) as the transport of the HTTP client.
+func WithHttpTransport(transport http.RoundTripper) ServiceOption {
+	return func(c *serviceConfig) {
+		c.transport = transport
+	}
+}
+
+// Sets the TLS configuration (custom root CAs, client certificates etc.) of the transport.
+// The option has effect only if the transport is an *http.Transport (which is the default).
+func WithTlsConfig(config *tls.Config) ServiceOption {
+	return func(c *serviceConfig) {
+		c.tlsConfig = config
+	}
+}
+
+// Sends all requests through the given proxy.
+// The option has effect only if the transport is an *http.Transport (which is the default).
+func WithProxy(proxyUrl *url.URL) ServiceOption {
+	return func(c *serviceConfig) {
+		c.proxy = http.ProxyURL(proxyUrl)
+	}
+}
+
+// Limits the number of concurrent requests made by the service. The default is 10.
+func WithMaxConn(maxConn uint) ServiceOption {
+	return func(c *serviceConfig) {
+		c.maxConn = maxConn
+	}
+}
+
+// Sets a time limit for every request made by the service, which covers waiting for a free
+// connection, the round trip and reading the response.
+// Zero (the default) means no limit, besides the one imposed by the service context.
+func WithRequestTimeout(timeout time.Duration) ServiceOption {
+	return func(c *serviceConfig) {
+		c.requestTimeout = timeout
+	}
+}
+
+// Adds a header to every request made by the service.
+// The value replaces the one set by the library, if any (e.g. User-Agent).
+func WithDefaultHeader(key, value string) ServiceOption {
+	return func(c *serviceConfig) {
+		c.headers.Add(key, value)
+	}
+}
+
+// Same as calling SetBasicAuth on the created service.
+func WithBasicAuth(username, password string) ServiceOption {
+	return func(c *serviceConfig) {
+		c.basicAuth = base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	}
+}
+
+func (c *serviceConfig) httpClient() *http.Client {
+	client := new(http.Client)
+	if c.client != nil {
+		*client = *c.client
+	}
+	if c.transport != nil {
+		client.Transport = c.transport
+	}
+
+	if c.tlsConfig != nil || c.proxy != nil {
+		transport := client.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		if httpTransport, ok := transport.(*http.Transport); ok {
+			httpTransport = httpTransport.Clone()
+			if c.tlsConfig != nil {
+				httpTransport.TLSClientConfig = c.tlsConfig
+			}
+			if c.proxy != nil {
+				httpTransport.Proxy = c.proxy
+			}
+			client.Transport = httpTransport
+		}
+	}
+
+	return client
+}
@@ -1,63 +1,187 @@
+// Code generated by struct_generator from status_codes.json. DO NOT EDIT.
+
 package neo2go
 
 const (
-	Neo_ClientError_LegacyIndex_NoSuchIndex = "Neo.ClientError.LegacyIndex.NoSuchIndex"
-	Neo_ClientError_Request_Invalid = "Neo.ClientError.Request.Invalid"
-	Neo_ClientError_Request_InvalidFormat = "Neo.ClientError.Request.InvalidFormat"
-	Neo_ClientError_Schema_ConstraintAlreadyExists = "Neo.ClientError.Schema.ConstraintAlreadyExists"
-	Neo_ClientError_Schema_ConstraintVerificationFailure = "Neo.ClientError.Schema.ConstraintVerificationFailure"
-	Neo_ClientError_Schema_ConstraintViolation = "Neo.ClientError.Schema.ConstraintViolation"
-	Neo_ClientError_Schema_IllegalTokenName = "Neo.ClientError.Schema.IllegalTokenName"
-	Neo_ClientError_Schema_IndexAlreadyExists = "Neo.ClientError.Schema.IndexAlreadyExists"
-	Neo_ClientError_Schema_IndexBelongsToConstraint = "Neo.ClientError.Schema.IndexBelongsToConstraint"
-	Neo_ClientError_Schema_IndexLimitReached = "Neo.ClientError.Schema.IndexLimitReached"
-	Neo_ClientError_Schema_LabelLimitReached = "Neo.ClientError.Schema.LabelLimitReached"
-	Neo_ClientError_Schema_NoSuchConstraint = "Neo.ClientError.Schema.NoSuchConstraint"
-	Neo_ClientError_Schema_NoSuchIndex = "Neo.ClientError.Schema.NoSuchIndex"
-	Neo_ClientError_Security_AuthenticationFailed = "Neo.ClientError.Security.AuthenticationFailed"
-	Neo_ClientError_Security_AuthenticationRateLimit = "Neo.ClientError.Security.AuthenticationRateLimit"
-	Neo_ClientError_Security_AuthorizationFailed = "Neo.ClientError.Security.AuthorizationFailed"
-	Neo_ClientError_Statement_ArithmeticError = "Neo.ClientError.Statement.ArithmeticError"
-	Neo_ClientError_Statement_ConstraintViolation = "Neo.ClientError.Statement.ConstraintViolation"
-	Neo_ClientError_Statement_EntityNotFound = "Neo.ClientError.Statement.EntityNotFound"
-	Neo_ClientError_Statement_InvalidArguments = "Neo.ClientError.Statement.InvalidArguments"
-	Neo_ClientError_Statement_InvalidSemantics = "Neo.ClientError.Statement.InvalidSemantics"
-	Neo_ClientError_Statement_InvalidSyntax = "Neo.ClientError.Statement.InvalidSyntax"
-	Neo_ClientError_Statement_InvalidType = "Neo.ClientError.Statement.InvalidType"
-	Neo_ClientError_Statement_NoSuchLabel = "Neo.ClientError.Statement.NoSuchLabel"
-	Neo_ClientError_Statement_NoSuchProperty = "Neo.ClientError.Statement.NoSuchProperty"
-	Neo_ClientError_Statement_ParameterMissing = "Neo.ClientError.Statement.ParameterMissing"
-	Neo_ClientError_Transaction_ConcurrentRequest = "Neo.ClientError.Transaction.ConcurrentRequest"
+	Neo_ClientError_LegacyIndex_NoSuchIndex                = "Neo.ClientError.LegacyIndex.NoSuchIndex"
+	Neo_ClientError_Request_Invalid                        = "Neo.ClientError.Request.Invalid"
+	Neo_ClientError_Request_InvalidFormat                  = "Neo.ClientError.Request.InvalidFormat"
+	Neo_ClientError_Schema_ConstraintAlreadyExists         = "Neo.ClientError.Schema.ConstraintAlreadyExists"
+	Neo_ClientError_Schema_ConstraintVerificationFailure   = "Neo.ClientError.Schema.ConstraintVerificationFailure"
+	Neo_ClientError_Schema_ConstraintViolation             = "Neo.ClientError.Schema.ConstraintViolation"
+	Neo_ClientError_Schema_IllegalTokenName                = "Neo.ClientError.Schema.IllegalTokenName"
+	Neo_ClientError_Schema_IndexAlreadyExists              = "Neo.ClientError.Schema.IndexAlreadyExists"
+	Neo_ClientError_Schema_IndexBelongsToConstraint        = "Neo.ClientError.Schema.IndexBelongsToConstraint"
+	Neo_ClientError_Schema_IndexLimitReached               = "Neo.ClientError.Schema.IndexLimitReached"
+	Neo_ClientError_Schema_LabelLimitReached               = "Neo.ClientError.Schema.LabelLimitReached"
+	Neo_ClientError_Schema_NoSuchConstraint                = "Neo.ClientError.Schema.NoSuchConstraint"
+	Neo_ClientError_Schema_NoSuchIndex                     = "Neo.ClientError.Schema.NoSuchIndex"
+	Neo_ClientError_Security_AuthenticationFailed          = "Neo.ClientError.Security.AuthenticationFailed"
+	Neo_ClientError_Security_AuthenticationRateLimit       = "Neo.ClientError.Security.AuthenticationRateLimit"
+	Neo_ClientError_Security_AuthorizationFailed           = "Neo.ClientError.Security.AuthorizationFailed"
+	Neo_ClientError_Statement_ArithmeticError              = "Neo.ClientError.Statement.ArithmeticError"
+	Neo_ClientError_Statement_ConstraintViolation          = "Neo.ClientError.Statement.ConstraintViolation"
+	Neo_ClientError_Statement_EntityNotFound               = "Neo.ClientError.Statement.EntityNotFound"
+	Neo_ClientError_Statement_InvalidArguments             = "Neo.ClientError.Statement.InvalidArguments"
+	Neo_ClientError_Statement_InvalidSemantics             = "Neo.ClientError.Statement.InvalidSemantics"
+	Neo_ClientError_Statement_InvalidSyntax                = "Neo.ClientError.Statement.InvalidSyntax"
+	Neo_ClientError_Statement_InvalidType                  = "Neo.ClientError.Statement.InvalidType"
+	Neo_ClientError_Statement_NoSuchLabel                  = "Neo.ClientError.Statement.NoSuchLabel"
+	Neo_ClientError_Statement_NoSuchProperty               = "Neo.ClientError.Statement.NoSuchProperty"
+	Neo_ClientError_Statement_ParameterMissing             = "Neo.ClientError.Statement.ParameterMissing"
+	Neo_ClientError_Transaction_ConcurrentRequest          = "Neo.ClientError.Transaction.ConcurrentRequest"
 	Neo_ClientError_Transaction_EventHandlerThrewException = "Neo.ClientError.Transaction.EventHandlerThrewException"
-	Neo_ClientError_Transaction_HookFailed = "Neo.ClientError.Transaction.HookFailed"
-	Neo_ClientError_Transaction_InvalidType = "Neo.ClientError.Transaction.InvalidType"
-	Neo_ClientError_Transaction_MarkedAsFailed = "Neo.ClientError.Transaction.MarkedAsFailed"
-	Neo_ClientError_Transaction_UnknownId = "Neo.ClientError.Transaction.UnknownId"
-	Neo_ClientError_Transaction_ValidationFailed = "Neo.ClientError.Transaction.ValidationFailed"
-	Neo_ClientNotification_Statement_CartesianProduct = "Neo.ClientNotification.Statement.CartesianProduct"
-	Neo_ClientNotification_Statement_DeprecationWarning = "Neo.ClientNotification.Statement.DeprecationWarning"
-	Neo_DatabaseError_General_CorruptSchemaRule = "Neo.DatabaseError.General.CorruptSchemaRule"
-	Neo_DatabaseError_General_FailedIndex = "Neo.DatabaseError.General.FailedIndex"
-	Neo_DatabaseError_General_UnknownFailure = "Neo.DatabaseError.General.UnknownFailure"
-	Neo_DatabaseError_Schema_ConstraintCreationFailure = "Neo.DatabaseError.Schema.ConstraintCreationFailure"
-	Neo_DatabaseError_Schema_ConstraintDropFailure = "Neo.DatabaseError.Schema.ConstraintDropFailure"
-	Neo_DatabaseError_Schema_IndexCreationFailure = "Neo.DatabaseError.Schema.IndexCreationFailure"
-	Neo_DatabaseError_Schema_IndexDropFailure = "Neo.DatabaseError.Schema.IndexDropFailure"
-	Neo_DatabaseError_Schema_NoSuchLabel = "Neo.DatabaseError.Schema.NoSuchLabel"
-	Neo_DatabaseError_Schema_NoSuchPropertyKey = "Neo.DatabaseError.Schema.NoSuchPropertyKey"
-	Neo_DatabaseError_Schema_NoSuchRelationshipType = "Neo.DatabaseError.Schema.NoSuchRelationshipType"
-	Neo_DatabaseError_Schema_NoSuchSchemaRule = "Neo.DatabaseError.Schema.NoSuchSchemaRule"
-	Neo_DatabaseError_Statement_ExecutionFailure = "Neo.DatabaseError.Statement.ExecutionFailure"
-	Neo_DatabaseError_Transaction_CouldNotBegin = "Neo.DatabaseError.Transaction.CouldNotBegin"
-	Neo_DatabaseError_Transaction_CouldNotCommit = "Neo.DatabaseError.Transaction.CouldNotCommit"
-	Neo_DatabaseError_Transaction_CouldNotRollback = "Neo.DatabaseError.Transaction.CouldNotRollback"
-	Neo_DatabaseError_Transaction_CouldNotWriteToLog = "Neo.DatabaseError.Transaction.CouldNotWriteToLog"
-	Neo_DatabaseError_Transaction_ReleaseLocksFailed = "Neo.DatabaseError.Transaction.ReleaseLocksFailed"
-	Neo_TransientError_General_DatabaseUnavailable = "Neo.TransientError.General.DatabaseUnavailable"
-	Neo_TransientError_Network_UnknownFailure = "Neo.TransientError.Network.UnknownFailure"
-	Neo_TransientError_Schema_ModifiedConcurrently = "Neo.TransientError.Schema.ModifiedConcurrently"
-	Neo_TransientError_Security_ModifiedConcurrently = "Neo.TransientError.Security.ModifiedConcurrently"
-	Neo_TransientError_Statement_ExternalResourceFailure = "Neo.TransientError.Statement.ExternalResourceFailure"
-	Neo_TransientError_Transaction_AcquireLockTimeout = "Neo.TransientError.Transaction.AcquireLockTimeout"
-	Neo_TransientError_Transaction_DeadlockDetected = "Neo.TransientError.Transaction.DeadlockDetected"
+	Neo_ClientError_Transaction_HookFailed                 = "Neo.ClientError.Transaction.HookFailed"
+	Neo_ClientError_Transaction_InvalidType                = "Neo.ClientError.Transaction.InvalidType"
+	Neo_ClientError_Transaction_MarkedAsFailed             = "Neo.ClientError.Transaction.MarkedAsFailed"
+	Neo_ClientError_Transaction_UnknownId                  = "Neo.ClientError.Transaction.UnknownId"
+	Neo_ClientError_Transaction_ValidationFailed           = "Neo.ClientError.Transaction.ValidationFailed"
+	Neo_ClientNotification_Statement_CartesianProduct      = "Neo.ClientNotification.Statement.CartesianProduct"
+	Neo_ClientNotification_Statement_DeprecationWarning    = "Neo.ClientNotification.Statement.DeprecationWarning"
+	Neo_DatabaseError_General_CorruptSchemaRule            = "Neo.DatabaseError.General.CorruptSchemaRule"
+	Neo_DatabaseError_General_FailedIndex                  = "Neo.DatabaseError.General.FailedIndex"
+	Neo_DatabaseError_General_UnknownFailure               = "Neo.DatabaseError.General.UnknownFailure"
+	Neo_DatabaseError_Schema_ConstraintCreationFailure     = "Neo.DatabaseError.Schema.ConstraintCreationFailure"
+	Neo_DatabaseError_Schema_ConstraintDropFailure         = "Neo.DatabaseError.Schema.ConstraintDropFailure"
+	Neo_DatabaseError_Schema_IndexCreationFailure          = "Neo.DatabaseError.Schema.IndexCreationFailure"
+	Neo_DatabaseError_Schema_IndexDropFailure              = "Neo.DatabaseError.Schema.IndexDropFailure"
+	Neo_DatabaseError_Schema_NoSuchLabel                   = "Neo.DatabaseError.Schema.NoSuchLabel"
+	Neo_DatabaseError_Schema_NoSuchPropertyKey             = "Neo.DatabaseError.Schema.NoSuchPropertyKey"
+	Neo_DatabaseError_Schema_NoSuchRelationshipType        = "Neo.DatabaseError.Schema.NoSuchRelationshipType"
+	Neo_DatabaseError_Schema_NoSuchSchemaRule              = "Neo.DatabaseError.Schema.NoSuchSchemaRule"
+	Neo_DatabaseError_Statement_ExecutionFailure           = "Neo.DatabaseError.Statement.ExecutionFailure"
+	Neo_DatabaseError_Transaction_CouldNotBegin            = "Neo.DatabaseError.Transaction.CouldNotBegin"
+	Neo_DatabaseError_Transaction_CouldNotCommit           = "Neo.DatabaseError.Transaction.CouldNotCommit"
+	Neo_DatabaseError_Transaction_CouldNotRollback         = "Neo.DatabaseError.Transaction.CouldNotRollback"
+	Neo_DatabaseError_Transaction_CouldNotWriteToLog       = "Neo.DatabaseError.Transaction.CouldNotWriteToLog"
+	Neo_DatabaseError_Transaction_ReleaseLocksFailed       = "Neo.DatabaseError.Transaction.ReleaseLocksFailed"
+	Neo_TransientError_General_DatabaseUnavailable         = "Neo.TransientError.General.DatabaseUnavailable"
+	Neo_TransientError_Network_UnknownFailure              = "Neo.TransientError.Network.UnknownFailure"
+	Neo_TransientError_Schema_ModifiedConcurrently         = "Neo.TransientError.Schema.ModifiedConcurrently"
+	Neo_TransientError_Security_ModifiedConcurrently       = "Neo.TransientError.Security.ModifiedConcurrently"
+	Neo_TransientError_Statement_ExternalResourceFailure   = "Neo.TransientError.Statement.ExternalResourceFailure"
+	Neo_TransientError_Transaction_AcquireLockTimeout      = "Neo.TransientError.Transaction.AcquireLockTimeout"
+	Neo_TransientError_Transaction_DeadlockDetected        = "Neo.TransientError.Transaction.DeadlockDetected"
+)
+
+// Matches all errors with the given classification, when used with errors.Is.
+var (
+	ErrClientError        = &NeoStatusClass{Classification: "ClientError"}
+	ErrClientNotification = &NeoStatusClass{Classification: "ClientNotification"}
+	ErrDatabaseError      = &NeoStatusClass{Classification: "DatabaseError"}
+	ErrTransientError     = &NeoStatusClass{Classification: "TransientError"}
 )
+
+// Matches all errors with the given category (regardless of the classification), when used with errors.Is.
+var (
+	ErrGeneralCategory     = &NeoStatusClass{Category: "General"}
+	ErrLegacyIndexCategory = &NeoStatusClass{Category: "LegacyIndex"}
+	ErrNetworkCategory     = &NeoStatusClass{Category: "Network"}
+	ErrRequestCategory     = &NeoStatusClass{Category: "Request"}
+	ErrSchemaCategory      = &NeoStatusClass{Category: "Schema"}
+	ErrSecurityCategory    = &NeoStatusClass{Category: "Security"}
+	ErrStatementCategory   = &NeoStatusClass{Category: "Statement"}
+	ErrTransactionCategory = &NeoStatusClass{Category: "Transaction"}
+)
+
+// A Neo4j status code, with the metadata from the status codes table.
+type NeoStatusCode string
+
+type neoStatusCodeInfo struct {
+	classification string
+	category       string
+	title          string
+	description    string
+	retryable      bool
+}
+
+// Returns the status code, and whether it is known (present in the status codes table).
+func LookupNeoStatusCode(code string) (NeoStatusCode, bool) {
+	_, ok := neoStatusCodes[NeoStatusCode(code)]
+	return NeoStatusCode(code), ok
+}
+
+// E.g. "ClientError".
+func (c NeoStatusCode) Classification() string {
+	return neoStatusCodes[c].classification
+}
+
+// E.g. "Schema".
+func (c NeoStatusCode) Category() string {
+	return neoStatusCodes[c].category
+}
+
+func (c NeoStatusCode) Title() string {
+	return neoStatusCodes[c].title
+}
+
+func (c NeoStatusCode) Description() string {
+	return neoStatusCodes[c].description
+}
+
+// Returns true if the operation which has failed with this status code may succeed when retried.
+func (c NeoStatusCode) IsRetryable() bool {
+	return neoStatusCodes[c].retryable
+}
+
+var neoStatusCodes = map[NeoStatusCode]neoStatusCodeInfo{
+	Neo_ClientError_LegacyIndex_NoSuchIndex:                {"ClientError", "LegacyIndex", "No such index", "The request (directly or indirectly) referred to an index that does not exist.", false},
+	Neo_ClientError_Request_Invalid:                        {"ClientError", "Request", "Invalid", "The client provided an invalid request.", false},
+	Neo_ClientError_Request_InvalidFormat:                  {"ClientError", "Request", "Invalid format", "The client provided a request that was missing required fields, or had values that are not allowed.", false},
+	Neo_ClientError_Schema_ConstraintAlreadyExists:         {"ClientError", "Schema", "Constraint already exists", "Unable to perform operation because it would clash with a pre-existing constraint.", false},
+	Neo_ClientError_Schema_ConstraintVerificationFailure:   {"ClientError", "Schema", "Constraint verification failure", "Unable to create constraint because data that exists in the database violates it.", false},
+	Neo_ClientError_Schema_ConstraintViolation:             {"ClientError", "Schema", "Constraint violation", "A constraint imposed by the database was violated.", false},
+	Neo_ClientError_Schema_IllegalTokenName:                {"ClientError", "Schema", "Illegal token name", "A token name, such as a label, relationship type or property key, used is not valid. Tokens cannot be empty strings and cannot be null.", false},
+	Neo_ClientError_Schema_IndexAlreadyExists:              {"ClientError", "Schema", "Index already exists", "Unable to perform operation because it would clash with a pre-existing index.", false},
+	Neo_ClientError_Schema_IndexBelongsToConstraint:        {"ClientError", "Schema", "Index belongs to constraint", "A requested operation can not be performed on the specified index because the index is part of a constraint. If you want to drop the index, for instance, you must drop the constraint.", false},
+	Neo_ClientError_Schema_IndexLimitReached:               {"ClientError", "Schema", "Index limit reached", "The maximum number of index entries supported has been reached, no more entities can be indexed.", false},
+	Neo_ClientError_Schema_LabelLimitReached:               {"ClientError", "Schema", "Label limit reached", "The maximum number of labels supported has been reached, no more labels can be created.", false},
+	Neo_ClientError_Schema_NoSuchConstraint:                {"ClientError", "Schema", "No such constraint", "The request (directly or indirectly) referred to a constraint that does not exist.", false},
+	Neo_ClientError_Schema_NoSuchIndex:                     {"ClientError", "Schema", "No such index", "The request (directly or indirectly) referred to an index that does not exist.", false},
+	Neo_ClientError_Security_AuthenticationFailed:          {"ClientError", "Security", "Authentication failed", "The client provided an incorrect username and/or password.", false},
+	Neo_ClientError_Security_AuthenticationRateLimit:       {"ClientError", "Security", "Authentication rate limit", "The client has provided incorrect authentication details too many times in a row.", false},
+	Neo_ClientError_Security_AuthorizationFailed:           {"ClientError", "Security", "Authorization failed", "The client does not have privileges to perform the operation requested.", false},
+	Neo_ClientError_Statement_ArithmeticError:              {"ClientError", "Statement", "Arithmetic error", "Invalid use of arithmetic, such as dividing by zero.", false},
+	Neo_ClientError_Statement_ConstraintViolation:          {"ClientError", "Statement", "Constraint violation", "A constraint imposed by the statement is violated by the data in the database.", false},
+	Neo_ClientError_Statement_EntityNotFound:               {"ClientError", "Statement", "Entity not found", "The statement is directly referring to an entity that does not exist.", false},
+	Neo_ClientError_Statement_InvalidArguments:             {"ClientError", "Statement", "Invalid arguments", "The statement is attempting to perform operations using invalid arguments.", false},
+	Neo_ClientError_Statement_InvalidSemantics:             {"ClientError", "Statement", "Invalid semantics", "The statement is syntactically valid, but expresses something that the database cannot do.", false},
+	Neo_ClientError_Statement_InvalidSyntax:                {"ClientError", "Statement", "Invalid syntax", "The statement contains invalid or unsupported syntax.", false},
+	Neo_ClientError_Statement_InvalidType:                  {"ClientError", "Statement", "Invalid type", "The statement is attempting to perform operations on values with types that are not supported by the operation.", false},
+	Neo_ClientError_Statement_NoSuchLabel:                  {"ClientError", "Statement", "No such label", "The statement is referring to a label that does not exist.", false},
+	Neo_ClientError_Statement_NoSuchProperty:               {"ClientError", "Statement", "No such property", "The statement is referring to a property that does not exist.", false},
+	Neo_ClientError_Statement_ParameterMissing:             {"ClientError", "Statement", "Parameter missing", "The statement is referring to a parameter that was not provided in the request.", false},
+	Neo_ClientError_Transaction_ConcurrentRequest:          {"ClientError", "Transaction", "Concurrent request", "There were concurrent requests accessing the same transaction, which is not allowed.", false},
+	Neo_ClientError_Transaction_EventHandlerThrewException: {"ClientError", "Transaction", "Event handler threw exception", "A transaction event handler threw an exception. The transaction will be rolled back.", false},
+	Neo_ClientError_Transaction_HookFailed:                 {"ClientError", "Transaction", "Hook failed", "Transaction hook failure.", false},
+	Neo_ClientError_Transaction_InvalidType:                {"ClientError", "Transaction", "Invalid type", "The transaction is of the wrong type to service the request. For instance, a transaction that has had schema modifications performed in it cannot be used to subsequently perform data operations, and vice versa.", false},
+	Neo_ClientError_Transaction_MarkedAsFailed:             {"ClientError", "Transaction", "Marked as failed", "Transaction was marked as both successful and failed. Failure takes precedence and so this transaction was rolled back although it may have looked like it was going to be committed.", false},
+	Neo_ClientError_Transaction_UnknownId:                  {"ClientError", "Transaction", "Unknown id", "The request referred to a transaction that does not exist.", false},
+	Neo_ClientError_Transaction_ValidationFailed:           {"ClientError", "Transaction", "Validation failed", "Transaction changes did not pass validation checks.", false},
+	Neo_ClientNotification_Statement_CartesianProduct:      {"ClientNotification", "Statement", "Cartesian product", "This query builds a cartesian product between disconnected patterns.", false},
+	Neo_ClientNotification_Statement_DeprecationWarning:    {"ClientNotification", "Statement", "Deprecation warning", "This feature is deprecated and will be removed in future versions.", false},
+	Neo_DatabaseError_General_CorruptSchemaRule:            {"DatabaseError", "General", "Corrupt schema rule", "A malformed schema rule was encountered. Please contact your support representative.", false},
+	Neo_DatabaseError_General_FailedIndex:                  {"DatabaseError", "General", "Failed index", "The request (directly or indirectly) referred to an index that is in a failed state. The index needs to be dropped and recreated manually.", false},
+	Neo_DatabaseError_General_UnknownFailure:               {"DatabaseError", "General", "Unknown failure", "An unknown failure occurred.", false},
+	Neo_DatabaseError_Schema_ConstraintCreationFailure:     {"DatabaseError", "Schema", "Constraint creation failure", "Creating a requested constraint failed.", false},
+	Neo_DatabaseError_Schema_ConstraintDropFailure:         {"DatabaseError", "Schema", "Constraint drop failure", "The database failed to drop a requested constraint.", false},
+	Neo_DatabaseError_Schema_IndexCreationFailure:          {"DatabaseError", "Schema", "Index creation failure", "Failed to create an index.", false},
+	Neo_DatabaseError_Schema_IndexDropFailure:              {"DatabaseError", "Schema", "Index drop failure", "The database failed to drop a requested index.", false},
+	Neo_DatabaseError_Schema_NoSuchLabel:                   {"DatabaseError", "Schema", "No such label", "The request accessed a label that did not exist.", false},
+	Neo_DatabaseError_Schema_NoSuchPropertyKey:             {"DatabaseError", "Schema", "No such property key", "The request accessed a property that does not exist.", false},
+	Neo_DatabaseError_Schema_NoSuchRelationshipType:        {"DatabaseError", "Schema", "No such relationship type", "The request accessed a relationship type that does not exist.", false},
+	Neo_DatabaseError_Schema_NoSuchSchemaRule:              {"DatabaseError", "Schema", "No such schema rule", "The request referred to a schema rule that does not exist.", false},
+	Neo_DatabaseError_Statement_ExecutionFailure:           {"DatabaseError", "Statement", "Execution failure", "The database was unable to execute the statement.", false},
+	Neo_DatabaseError_Transaction_CouldNotBegin:            {"DatabaseError", "Transaction", "Could not begin", "The database was unable to start the transaction.", false},
+	Neo_DatabaseError_Transaction_CouldNotCommit:           {"DatabaseError", "Transaction", "Could not commit", "The database was unable to commit the transaction.", false},
+	Neo_DatabaseError_Transaction_CouldNotRollback:         {"DatabaseError", "Transaction", "Could not rollback", "The database was unable to roll back the transaction.", false},
+	Neo_DatabaseError_Transaction_CouldNotWriteToLog:       {"DatabaseError", "Transaction", "Could not write to log", "The database was unable to write transaction to log.", false},
+	Neo_DatabaseError_Transaction_ReleaseLocksFailed:       {"DatabaseError", "Transaction", "Release locks failed", "The transaction was unable to release one or more of its locks.", false},
+	Neo_TransientError_General_DatabaseUnavailable:         {"TransientError", "General", "Database unavailable", "The database is not currently available to serve your request, refer to the database logs for more details. Retrying your request at a later time may succeed.", true},
+	Neo_TransientError_Network_UnknownFailure:              {"TransientError", "Network", "Unknown failure", "An unknown network failure occurred, a retry may resolve the issue.", true},
+	Neo_TransientError_Schema_ModifiedConcurrently:         {"TransientError", "Schema", "Modified concurrently", "The database schema was modified while this transaction was running, the transaction should be retried.", true},
+	Neo_TransientError_Security_ModifiedConcurrently:       {"TransientError", "Security", "Modified concurrently", "The user was modified concurrently to this request.", true},
+	Neo_TransientError_Statement_ExternalResourceFailure:   {"TransientError", "Statement", "External resource failure", "The external resource is not available.", true},
+	Neo_TransientError_Transaction_AcquireLockTimeout:      {"TransientError", "Transaction", "Acquire lock timeout", "The transaction was unable to acquire a lock, for instance due to a timeout or the transaction thread being interrupted.", true},
+	Neo_TransientError_Transaction_DeadlockDetected:        {"TransientError", "Transaction", "Deadlock detected", "This transaction, and at least one more transaction, has acquired locks in a way that it will wait indefinitely, and the database has aborted it. Retrying this transaction will most likely be successful.", true},
+}
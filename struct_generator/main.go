@@ -1,12 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"go/format"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"unicode"
 )
 
 var inputFilePath *string = flag.String("f", "", "Json input file")
@@ -34,34 +39,158 @@ func main() {
 	}
 	defer inputFile.Close()
 
-	idList := make([]string, 0)
+	statusCodes := make([]statusCode, 0)
 	decoder := json.NewDecoder(inputFile)
-	err = decoder.Decode(&idList)
+	err = decoder.Decode(&statusCodes)
 	if err != nil {
 		log.Fatalf("Could not decode JSON: %v\n", err)
 	}
 
+	var buf bytes.Buffer
+	write := func(s string) {
+		buf.WriteString(s)
+	}
+
+	write("// Code generated by struct_generator from " + filepath.Base(*inputFilePath) + ". DO NOT EDIT.\n\n")
+	write("package " + *outputPackageName + "\n\n")
+	write("const (\n")
+
+	classifications := make(map[string]bool)
+	categories := make(map[string]bool)
+	for i := range statusCodes {
+		code := &statusCodes[i]
+		parts := strings.Split(code.Code, ".")
+		if len(parts) != 4 || parts[0] != "Neo" {
+			log.Fatalf("Invalid status code: %v\n", code.Code)
+		}
+		code.classification, code.category, code.name = parts[1], parts[2], parts[3]
+		classifications[code.classification] = true
+		categories[code.category] = true
+
+		id := strings.Replace(code.Code, ".", "_", -1)
+		write(fmt.Sprintf("\t%s = \"%s\"\n", id, code.Code))
+	}
+
+	write(")\n\n")
+
+	write("// Matches all errors with the given classification, when used with errors.Is.\n")
+	write("var (\n")
+	for _, classification := range sortedKeys(classifications) {
+		write(fmt.Sprintf("\tErr%s = &NeoStatusClass{Classification: \"%s\"}\n", classification, classification))
+	}
+	write(")\n\n")
+
+	write("// Matches all errors with the given category (regardless of the classification), when used with errors.Is.\n")
+	write("var (\n")
+	for _, category := range sortedKeys(categories) {
+		write(fmt.Sprintf("\tErr%sCategory = &NeoStatusClass{Category: \"%s\"}\n", category, category))
+	}
+	write(")\n\n")
+
+	write(statusCodeType)
+	write("var neoStatusCodes = map[NeoStatusCode]neoStatusCodeInfo{\n")
+	for _, code := range statusCodes {
+		title := code.Title
+		if title == "" {
+			title = titleFromName(code.name)
+		}
+		retryable := code.classification == "TransientError"
+		if code.Retryable != nil {
+			retryable = *code.Retryable
+		}
+		write(fmt.Sprintf("\t%s: {%q, %q, %q, %q, %v},\n", strings.Replace(code.Code, ".", "_", -1),
+			code.classification, code.category, title, code.Description, retryable))
+	}
+	write("}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("Could not format the generated code: %v\n", err)
+	}
+
 	outputFile, err := os.Create(*outputFilePath)
 	if err != nil {
 		log.Fatalf("Could not create output file: %v\n", err)
 	}
 	defer outputFile.Close()
 
-	var writeErr error
-	write := func(s string) {
-		if writeErr != nil {
-			return
-		}
-		_, writeErr = outputFile.WriteString(s)
+	if _, err := outputFile.Write(source); err != nil {
+		log.Fatalf("Could not write output file: %v\n", err)
 	}
+}
 
-	write("package " + *outputPackageName + "\n\n")
-	write("const (\n")
+type statusCode struct {
+	Code        string `json:"code"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Defaults to true for transient errors.
+	Retryable *bool `json:"retryable"`
+
+	classification string
+	category       string
+	name           string
+}
+
+const statusCodeType = `// A Neo4j status code, with the metadata from the status codes table.
+type NeoStatusCode string
+
+type neoStatusCodeInfo struct {
+	classification string
+	category       string
+	title          string
+	description    string
+	retryable      bool
+}
+
+// Returns the status code, and whether it is known (present in the status codes table).
+func LookupNeoStatusCode(code string) (NeoStatusCode, bool) {
+	_, ok := neoStatusCodes[NeoStatusCode(code)]
+	return NeoStatusCode(code), ok
+}
+
+// E.g. "ClientError".
+func (c NeoStatusCode) Classification() string {
+	return neoStatusCodes[c].classification
+}
 
-	for _, line := range idList {
-		id := strings.Replace(line, ".", "_", -1)
-		write(fmt.Sprintf("\t%s = \"%s\"\n", id, line))
+// E.g. "Schema".
+func (c NeoStatusCode) Category() string {
+	return neoStatusCodes[c].category
+}
+
+func (c NeoStatusCode) Title() string {
+	return neoStatusCodes[c].title
+}
+
+func (c NeoStatusCode) Description() string {
+	return neoStatusCodes[c].description
+}
+
+// Returns true if the operation which has failed with this status code may succeed when retried.
+func (c NeoStatusCode) IsRetryable() bool {
+	return neoStatusCodes[c].retryable
+}
+
+`
+
+// "NoSuchIndex" -> "No such index"
+func titleFromName(name string) string {
+	var buf bytes.Buffer
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			buf.WriteRune(' ')
+			r = unicode.ToLower(r)
+		}
+		buf.WriteRune(r)
 	}
+	return buf.String()
+}
 
-	write(")\n")
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
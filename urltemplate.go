@@ -3,29 +3,70 @@ package neo2go
 import (
 	"bytes"
 	"fmt"
-	"net/url"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
-var paramsRe, listParamRe, batchParamRe *regexp.Regexp
+var paramsRe, listParamRe, batchParamRe, varNameRe *regexp.Regexp
 
+// A variable of an expression.
 type urlParameter struct {
-	// Only used for list parameters.
+	// Only used for the legacy list parameters ({-list|delimiter|name}).
 	Delimiter string
 	// Name of the parameter.
 	Name string
-	// If true, it means the parameter is to be used as URL query, after the '?' sign, as name=value pair.
-	Queried bool
+	// The explode modifier ({name*}).
+	Explode bool
+	// The prefix modifier ({name:3}); 0 means the whole value is used.
+	MaxLength int
 }
 
+// An expression of the template (the text between braces).
+type urlExpression struct {
+	// One of the RFC 6570 operators (+ # . / ; ? &), or 0 for the simple string expansion.
+	Operator byte
+	Params   []urlParameter
+}
+
+// How the values of the variables are joined for an operator (see RFC 6570, appendix A).
+type urlOperator struct {
+	first         string
+	separator     string
+	named         bool
+	ifEmpty       string
+	allowReserved bool
+}
+
+var urlOperators = map[byte]urlOperator{
+	0:   {"", ",", false, "", false},
+	'+': {"", ",", false, "", true},
+	'.': {".", ".", false, "", false},
+	'/': {"/", "/", false, "", false},
+	';': {";", ";", true, "", false},
+	'?': {"?", "&", true, "=", false},
+	'&': {"&", "&", true, "=", false},
+	'#': {"#", ",", false, "", true},
+}
+
+// A URI template, as defined by RFC 6570 (levels 1-4), which also understands the syntax used
+// by Neo4j: the legacy {-list|delimiter|name} lists, and the {N} references of the batch jobs,
+// which are left as they are.
+//
+// The values of the variables are strings, lists ([]string) or associative arrays (map[string]string,
+// whose keys are sorted, or [][2]string key-value pairs, whose order is kept). A nil value,
+// an empty list or an empty associative array means the variable is undefined.
 type UrlTemplate struct {
 	// Contains the string as passed by the server.
 	template string
 	// Contains either a 2-element int array of indices that correspond
 	// to sections of the template which do not need to be rendered;
-	// or a urlParameter.
+	// or a urlExpression.
 	sections []interface{}
+	// The error found while parsing the template, returned by Render and Expand.
+	err error
 }
 
 func NewUrlTemplate(url string) *UrlTemplate {
@@ -60,10 +101,10 @@ func (u *UrlTemplate) parse() {
 		s := strings.Trim(paramString, "{}")
 
 		matches := listParamRe.FindStringSubmatch(s)
-		if matches != nil || len(matches) == 3 {
+		if len(matches) == 3 {
 			u.parseList(matches)
-		} else {
-			u.parseNamedParams(s)
+		} else if err := u.parseExpression(s); err != nil && u.err == nil {
+			u.err = err
 		}
 	}
 	if prevIndex < len(u.template) {
@@ -79,82 +120,239 @@ func (u *UrlTemplate) parseList(matches []string) {
 	var param urlParameter
 	param.Delimiter = matches[1]
 	param.Name = matches[2]
-	u.sections = append(u.sections, param)
+	u.sections = append(u.sections, urlExpression{Params: []urlParameter{param}})
 }
 
-func (u *UrlTemplate) parseNamedParams(s string) {
-	queried := false
+func (u *UrlTemplate) parseExpression(s string) error {
+	var expression urlExpression
 
-	if s[0] == '?' {
-		queried = true
+	if strings.IndexByte("=,!@|", s[0]) >= 0 {
+		return fmt.Errorf("The operator '%c' of the URL template expression '{%v}' is reserved.", s[0], s)
+	}
+	if _, ok := urlOperators[s[0]]; ok {
+		expression.Operator = s[0]
 		s = s[1:]
 	}
 
-	paramNames := strings.Split(s, ",")
-	for _, name := range paramNames {
+	for _, varSpec := range strings.Split(s, ",") {
 		var param urlParameter
-		param.Queried = queried
-		param.Name = name
-		u.sections = append(u.sections, param)
+		if strings.HasSuffix(varSpec, "*") {
+			param.Explode = true
+			varSpec = varSpec[:len(varSpec)-1]
+		} else if i := strings.IndexByte(varSpec, ':'); i >= 0 {
+			maxLength, err := strconv.Atoi(varSpec[i+1:])
+			if err != nil || maxLength < 1 || maxLength > 9999 || varSpec[i+1] == '0' {
+				return fmt.Errorf("Invalid prefix modifier of the variable '%v' (use a number between 1 and 9999).", varSpec[:i])
+			}
+			param.MaxLength = maxLength
+			varSpec = varSpec[:i]
+		}
+		if !varNameRe.MatchString(varSpec) {
+			return fmt.Errorf("Invalid variable name '%v' in the URL template.", varSpec)
+		}
+		param.Name = varSpec
+		expression.Params = append(expression.Params, param)
 	}
+
+	u.sections = append(u.sections, expression)
+	return nil
 }
 
-func (u *UrlTemplate) renderUrlParameterIntoBuffer(urlparam *urlParameter, buf *bytes.Buffer, paramValue interface{}, questionMarkInserted *bool, wasLastSectionQueried *bool) (error, bool) {
-	if paramValue == nil && !urlparam.Queried {
-		return nil, true // true - should stop processing the template, even if there are more sections to process.
-	}
+// A value of a variable: a string, a list, or an associative array.
+type urlValue struct {
+	str   string
+	list  []string
+	pairs [][2]string
+	isStr bool
+}
 
-	if s, ok := paramValue.(string); ok {
-		if len(urlparam.Delimiter) > 0 {
-			return fmt.Errorf("The type of the value for key '%v' is a `string`, but `[]string` was expected."), true
+func newUrlValue(key string, value interface{}) (urlValue, error) {
+	switch v := value.(type) {
+	case nil:
+		return urlValue{}, nil
+	case string:
+		return urlValue{str: v, isStr: true}, nil
+	case []string:
+		return urlValue{list: v}, nil
+	case [][2]string:
+		return urlValue{pairs: v}, nil
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
 		}
+		sort.Strings(keys)
+		pairs := make([][2]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = [2]string{k, v[k]}
+		}
+		return urlValue{pairs: pairs}, nil
+	}
+	return urlValue{}, fmt.Errorf("The type of the value for key '%v' is not supported (use `string`, `[]string`, `map[string]string` or `[][2]string`).", key)
+}
 
-		if urlparam.Queried {
-			if !*questionMarkInserted {
-				buf.WriteString("?")
-				*questionMarkInserted = true
-			}
-			if *wasLastSectionQueried {
-				buf.WriteString("&")
+func (v *urlValue) isDefined() bool {
+	return v.isStr || len(v.list) > 0 || len(v.pairs) > 0
+}
+
+// Writes the value of the variable; returns true, if rendering should stop (e.g. because of an undefined variable).
+func (e *urlExpression) renderIntoBuffer(buf *bytes.Buffer, params map[string]interface{}, truncate bool) (error, bool) {
+	operator := urlOperators[e.Operator]
+	first := true
+
+	for i := range e.Params {
+		urlparam := &e.Params[i]
+		value, err := newUrlValue(urlparam.Name, params[urlparam.Name])
+		if err != nil {
+			return err, true
+		}
+		if !value.isDefined() {
+			if truncate && e.Operator == 0 {
+				return nil, true // true - should stop processing the template, even if there are more sections to process.
 			}
+			continue
+		}
 
-			buf.WriteString(url.QueryEscape(urlparam.Name))
-			buf.WriteString("=")
-			buf.WriteString(url.QueryEscape(s))
-			*wasLastSectionQueried = true
+		if first {
+			buf.WriteString(operator.first)
+			first = false
 		} else {
-			buf.WriteString(url.QueryEscape(s))
+			buf.WriteString(operator.separator)
 		}
-	} else if arr, ok := paramValue.([]string); ok {
-		if len(urlparam.Delimiter) == 0 {
-			return fmt.Errorf("The type of the value for key '%v' is a `[]string`, but `string` was expected."), true
+		if err := urlparam.renderIntoBuffer(buf, &operator, &value); err != nil {
+			return err, true
 		}
+	}
+
+	return nil, false
+}
 
-		maxIndexForPlacingDelimiter := len(arr) - 2
-		escapedDelimiter := url.QueryEscape(urlparam.Delimiter)
-		for i, s := range arr {
-			buf.WriteString(url.QueryEscape(s))
-			if i <= maxIndexForPlacingDelimiter {
+func (urlparam *urlParameter) renderIntoBuffer(buf *bytes.Buffer, operator *urlOperator, value *urlValue) error {
+	escape := func(s string) string {
+		return escapeUrlTemplateValue(s, operator.allowReserved)
+	}
+
+	if len(urlparam.Delimiter) > 0 {
+		if value.list == nil {
+			return fmt.Errorf("The type of the value for key '%v' is not `[]string`, which was expected.", urlparam.Name)
+		}
+		escapedDelimiter := escape(urlparam.Delimiter)
+		for i, s := range value.list {
+			if i > 0 {
 				buf.WriteString(escapedDelimiter)
 			}
+			buf.WriteString(escape(s))
 		}
-	} else if paramValue != nil {
-		return fmt.Errorf("The type of the value for key '%v' is not supported (use `string` or `[]string`).", urlparam.Name), true
+		return nil
 	}
 
-	return nil, false
+	if value.isStr {
+		if operator.named {
+			buf.WriteString(urlparam.Name)
+			if value.str == "" {
+				buf.WriteString(operator.ifEmpty)
+				return nil
+			}
+			buf.WriteString("=")
+		}
+		s := value.str
+		if urlparam.MaxLength > 0 && utf8.RuneCountInString(s) > urlparam.MaxLength {
+			s = string([]rune(s)[:urlparam.MaxLength])
+		}
+		buf.WriteString(escape(s))
+		return nil
+	}
+
+	if urlparam.MaxLength > 0 {
+		return fmt.Errorf("The prefix modifier cannot be applied to the composite value for key '%v'.", urlparam.Name)
+	}
+
+	if !urlparam.Explode {
+		if operator.named {
+			buf.WriteString(urlparam.Name)
+			buf.WriteString("=")
+		}
+		for i, s := range value.list {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(escape(s))
+		}
+		for i, pair := range value.pairs {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(escape(pair[0]))
+			buf.WriteString(",")
+			buf.WriteString(escape(pair[1]))
+		}
+		return nil
+	}
+
+	for i, s := range value.list {
+		if i > 0 {
+			buf.WriteString(operator.separator)
+		}
+		if operator.named {
+			buf.WriteString(urlparam.Name)
+			if s == "" {
+				buf.WriteString(operator.ifEmpty)
+				continue
+			}
+			buf.WriteString("=")
+		}
+		buf.WriteString(escape(s))
+	}
+	for i, pair := range value.pairs {
+		if i > 0 {
+			buf.WriteString(operator.separator)
+		}
+		buf.WriteString(escape(pair[0]))
+		if operator.named && pair[1] == "" {
+			buf.WriteString(operator.ifEmpty)
+			continue
+		}
+		buf.WriteString("=")
+		buf.WriteString(escape(pair[1]))
+	}
+	return nil
 }
 
-func (u *UrlTemplate) Render(params map[string]interface{}) (string, error) {
+// Percent-encodes all the characters except the unreserved ones, and (if allowReserved is set)
+// the reserved ones and the percent-encoded triplets.
+func escapeUrlTemplateValue(s string, allowReserved bool) string {
 	var buf bytes.Buffer
-	questionMarkInserted := false
-	wasLastSectionQueried := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', strings.IndexByte("-._~", c) >= 0:
+			buf.WriteByte(c)
+		case allowReserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0:
+			buf.WriteByte(c)
+		case allowReserved && c == '%' && i+2 < len(s) && isHexDigit(s[i+1]) && isHexDigit(s[i+2]):
+			buf.WriteByte(c)
+		default:
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+	return buf.String()
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func (u *UrlTemplate) render(params map[string]interface{}, truncate bool) (string, error) {
+	if u.err != nil {
+		return "", u.err
+	}
 
+	var buf bytes.Buffer
 	for _, section := range u.sections {
 		if indices, ok := section.([2]int); ok {
 			buf.WriteString(u.template[indices[0]:indices[1]])
-		} else if urlparam, ok := section.(urlParameter); ok {
-			err, shouldStop := u.renderUrlParameterIntoBuffer(&urlparam, &buf, params[urlparam.Name], &questionMarkInserted, &wasLastSectionQueried)
+		} else if expression, ok := section.(urlExpression); ok {
+			err, shouldStop := expression.renderIntoBuffer(&buf, params, truncate)
 			if err != nil {
 				return "", err
 			}
@@ -167,6 +365,18 @@ func (u *UrlTemplate) Render(params map[string]interface{}) (string, error) {
 	return buf.String(), nil
 }
 
+// Renders the URL the way Neo4j expects it: the rest of the template is dropped at the first undefined
+// variable of a simple ({name}) or list expression, so that e.g. "/index/node/name/{key}/{value}"
+// becomes "/index/node/name/" without parameters.
+func (u *UrlTemplate) Render(params map[string]interface{}) (string, error) {
+	return u.render(params, true)
+}
+
+// Expands the template as specified by RFC 6570: the undefined variables are omitted.
+func (u *UrlTemplate) Expand(params map[string]interface{}) (string, error) {
+	return u.render(params, false)
+}
+
 func (u *UrlTemplate) String() string {
 	return u.template
 }
@@ -174,6 +384,7 @@ func (u *UrlTemplate) String() string {
 func (u *UrlTemplate) UnmarshalJSON(data []byte) error {
 	u.template = ""
 	u.sections = u.sections[:0]
+	u.err = nil
 	s := string(data)
 	u.template = s[1 : len(s)-1]
 	u.parse()
@@ -184,4 +395,5 @@ func init() {
 	paramsRe = regexp.MustCompile(`{[^}]+}`)
 	listParamRe = regexp.MustCompile(`^-list\|([^\|]+)\|([^\s]+)$`)
 	batchParamRe = regexp.MustCompile(`{[0-9]+}`)
+	varNameRe = regexp.MustCompile(`^(?:[A-Za-z0-9_]|%[0-9A-Fa-f]{2})(?:\.?(?:[A-Za-z0-9_]|%[0-9A-Fa-f]{2}))*$`)
 }
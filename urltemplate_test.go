@@ -2,6 +2,7 @@ package neo2go
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -44,13 +45,14 @@ func TestParsingList(t *testing.T) {
 		t.Fatalf("Expected the first section to be of type `[]int`.")
 	}
 
-	if param, ok := tpl.sections[1].(urlParameter); ok {
-		if param.Delimiter != "&" {
-			t.Fatalf("Expected '&' delimiter, but got %v", param.Delimiter)
+	if expression, ok := tpl.sections[1].(urlExpression); ok {
+		if expression.Operator != 0 || len(expression.Params) != 1 {
+			t.Fatalf("Expected a simple expression with one parameter, but got %+v", expression)
 		}
 
-		if param.Queried {
-			t.Fatalf("Expected the 'Queried' property to be false.")
+		param := expression.Params[0]
+		if param.Delimiter != "&" {
+			t.Fatalf("Expected '&' delimiter, but got %v", param.Delimiter)
 		}
 
 		name := "types"
@@ -58,7 +60,7 @@ func TestParsingList(t *testing.T) {
 			t.Fatalf("Expected the parameter name to be %v, but got %v.", name, param.Name)
 		}
 	} else {
-		t.Fatalf("Expected second section to be of type `urlParameter`.")
+		t.Fatalf("Expected second section to be of type `urlExpression`.")
 	}
 }
 
@@ -69,9 +71,8 @@ func TestParsingNamedParams(t *testing.T) {
 
 	expected := []interface{}{
 		[2]int{0, 52},
-		urlParameter{"", "returnType", false},
-		urlParameter{"", "pageSize", true},
-		urlParameter{"", "leaseTime", true},
+		urlExpression{0, []urlParameter{{Name: "returnType"}}},
+		urlExpression{'?', []urlParameter{{Name: "pageSize"}, {Name: "leaseTime"}}},
 	}
 
 	if len(tpl.sections) != len(expected) {
@@ -79,35 +80,22 @@ func TestParsingNamedParams(t *testing.T) {
 	}
 
 	for i := 0; i < len(expected); i++ {
-		exemplary := expected[i]
-		parsed := tpl.sections[i]
-
-		exemplaryType := reflect.TypeOf(exemplary)
-		if exemplaryType != reflect.TypeOf(parsed) {
-			t.Fatalf("Types of parsed sections do not match.")
+		if !reflect.DeepEqual(expected[i], tpl.sections[i]) {
+			t.Fatalf("Expected '%v' section, but got %v", expected[i], tpl.sections[i])
 		}
+	}
+}
 
-		if exemplaryType == reflect.TypeOf([]int{}) {
-			exemplaryArray, _ := exemplary.([2]int)
-			parsedArray, _ := parsed.([2]int)
-			if exemplaryArray[0] != parsedArray[0] || exemplaryArray[1] != parsedArray[1] {
-				t.Fatalf("Expected '%v' section, but got %v", exemplaryArray, parsedArray)
-			}
-		} else {
-			exemplaryParam, _ := exemplary.(urlParameter)
-			parsedParam, _ := parsed.(urlParameter)
-
-			if exemplaryParam.Delimiter != parsedParam.Delimiter {
-				t.Fatalf("Expected '%v' delimiter, but got %v", exemplaryParam.Delimiter, parsedParam.Delimiter)
-			}
-
-			if exemplaryParam.Queried != parsedParam.Queried {
-				t.Fatalf("Expected the 'Queried' property to be %v, but got %v.", exemplaryParam.Queried, parsedParam.Queried)
-			}
-
-			if exemplaryParam.Name != parsedParam.Name {
-				t.Fatalf("Expected the parameter name to be %v, but got %v.", exemplaryParam.Name, parsedParam.Name)
-			}
+func TestParsingModifiers(t *testing.T) {
+	tpl := NewUrlTemplate("{/var:3,list*}")
+	expected := urlExpression{'/', []urlParameter{{Name: "var", MaxLength: 3}, {Name: "list", Explode: true}}}
+	if len(tpl.sections) != 1 || !reflect.DeepEqual(expected, tpl.sections[0]) {
+		t.Fatalf("Expected '%v' section, but got %v", expected, tpl.sections)
+	}
+
+	for _, invalid := range []string{"{=var}", "{var:0}", "{var:10000}", "{va r}", "{var,}"} {
+		if _, err := NewUrlTemplate(invalid).Render(nil); err == nil {
+			t.Errorf("Expected an error for the template '%v'.", invalid)
 		}
 	}
 }
@@ -314,3 +302,184 @@ func TestRenderingArray(t *testing.T) {
 		t.Fatalf("Expected to render '%v', but got '%v'", expected, s)
 	}
 }
+
+func TestRenderingListWithString(t *testing.T) {
+	tpl := NewUrlTemplate("http://localhost:7474/db/data/node/9/relationships/all/{-list|&|types}")
+	_, err := tpl.Render(map[string]interface{}{"types": "T1"})
+	if err == nil || !strings.Contains(err.Error(), "'types'") {
+		t.Fatalf("Expected an error mentioning the key, but got: %v", err)
+	}
+}
+
+// The examples from RFC 6570, section 3.2.
+var rfc6570Variables = map[string]interface{}{
+	"count":      []string{"one", "two", "three"},
+	"dom":        []string{"example", "com"},
+	"dub":        "me/too",
+	"hello":      "Hello World!",
+	"half":       "50%",
+	"var":        "value",
+	"who":        "fred",
+	"base":       "http://example.com/home/",
+	"path":       "/foo/bar",
+	"list":       []string{"red", "green", "blue"},
+	"keys":       [][2]string{{"semi", ";"}, {"dot", "."}, {"comma", ","}},
+	"v":          "6",
+	"x":          "1024",
+	"y":          "768",
+	"empty":      "",
+	"empty_keys": [][2]string{},
+	"undef":      nil,
+}
+
+var rfc6570Examples = [][2]string{
+	// 3.2.1. Variable Expansion
+	{"{count}", "one,two,three"},
+	{"{count*}", "one,two,three"},
+	{"{/count}", "/one,two,three"},
+	{"{/count*}", "/one/two/three"},
+	{"{;count}", ";count=one,two,three"},
+	{"{;count*}", ";count=one;count=two;count=three"},
+	{"{?count}", "?count=one,two,three"},
+	{"{?count*}", "?count=one&count=two&count=three"},
+	{"{&count*}", "&count=one&count=two&count=three"},
+	// 3.2.2. Simple String Expansion
+	{"{var}", "value"},
+	{"{hello}", "Hello%20World%21"},
+	{"{half}", "50%25"},
+	{"O{empty}X", "OX"},
+	{"O{undef}X", "OX"},
+	{"{x,y}", "1024,768"},
+	{"{x,hello,y}", "1024,Hello%20World%21,768"},
+	{"?{x,empty}", "?1024,"},
+	{"?{x,undef}", "?1024"},
+	{"?{undef,y}", "?768"},
+	{"{var:3}", "val"},
+	{"{var:30}", "value"},
+	{"{list}", "red,green,blue"},
+	{"{list*}", "red,green,blue"},
+	{"{keys}", "semi,%3B,dot,.,comma,%2C"},
+	{"{keys*}", "semi=%3B,dot=.,comma=%2C"},
+	// 3.2.3. Reserved Expansion
+	{"{+var}", "value"},
+	{"{+hello}", "Hello%20World!"},
+	{"{+half}", "50%25"},
+	{"{base}index", "http%3A%2F%2Fexample.com%2Fhome%2Findex"},
+	{"{+base}index", "http://example.com/home/index"},
+	{"O{+empty}X", "OX"},
+	{"O{+undef}X", "OX"},
+	{"{+path}/here", "/foo/bar/here"},
+	{"here?ref={+path}", "here?ref=/foo/bar"},
+	{"up{+path}{var}/here", "up/foo/barvalue/here"},
+	{"{+x,hello,y}", "1024,Hello%20World!,768"},
+	{"{+path,x}/here", "/foo/bar,1024/here"},
+	{"{+path:6}/here", "/foo/b/here"},
+	{"{+list}", "red,green,blue"},
+	{"{+list*}", "red,green,blue"},
+	{"{+keys}", "semi,;,dot,.,comma,,"},
+	{"{+keys*}", "semi=;,dot=.,comma=,"},
+	// 3.2.4. Fragment Expansion
+	{"{#var}", "#value"},
+	{"{#hello}", "#Hello%20World!"},
+	{"{#half}", "#50%25"},
+	{"foo{#empty}", "foo#"},
+	{"foo{#undef}", "foo"},
+	{"{#x,hello,y}", "#1024,Hello%20World!,768"},
+	{"{#path,x}/here", "#/foo/bar,1024/here"},
+	{"{#path:6}/here", "#/foo/b/here"},
+	{"{#list}", "#red,green,blue"},
+	{"{#list*}", "#red,green,blue"},
+	{"{#keys}", "#semi,;,dot,.,comma,,"},
+	{"{#keys*}", "#semi=;,dot=.,comma=,"},
+	// 3.2.5. Label Expansion with Dot-Prefix
+	{"{.who}", ".fred"},
+	{"{.who,who}", ".fred.fred"},
+	{"{.half,who}", ".50%25.fred"},
+	{"www{.dom*}", "www.example.com"},
+	{"X{.var}", "X.value"},
+	{"X{.empty}", "X."},
+	{"X{.undef}", "X"},
+	{"X{.var:3}", "X.val"},
+	{"X{.list}", "X.red,green,blue"},
+	{"X{.list*}", "X.red.green.blue"},
+	{"X{.keys}", "X.semi,%3B,dot,.,comma,%2C"},
+	{"X{.keys*}", "X.semi=%3B.dot=..comma=%2C"},
+	{"X{.empty_keys}", "X"},
+	{"X{.empty_keys*}", "X"},
+	// 3.2.6. Path Segment Expansion
+	{"{/who}", "/fred"},
+	{"{/who,who}", "/fred/fred"},
+	{"{/half,who}", "/50%25/fred"},
+	{"{/who,dub}", "/fred/me%2Ftoo"},
+	{"{/var}", "/value"},
+	{"{/var,empty}", "/value/"},
+	{"{/var,undef}", "/value"},
+	{"{/var,x}/here", "/value/1024/here"},
+	{"{/var:1,var}", "/v/value"},
+	{"{/list}", "/red,green,blue"},
+	{"{/list*}", "/red/green/blue"},
+	{"{/list*,path:4}", "/red/green/blue/%2Ffoo"},
+	{"{/keys}", "/semi,%3B,dot,.,comma,%2C"},
+	{"{/keys*}", "/semi=%3B/dot=./comma=%2C"},
+	// 3.2.7. Path-Style Parameter Expansion
+	{"{;who}", ";who=fred"},
+	{"{;half}", ";half=50%25"},
+	{"{;empty}", ";empty"},
+	{"{;v,empty,who}", ";v=6;empty;who=fred"},
+	{"{;v,bar,who}", ";v=6;who=fred"},
+	{"{;x,y}", ";x=1024;y=768"},
+	{"{;x,y,empty}", ";x=1024;y=768;empty"},
+	{"{;x,y,undef}", ";x=1024;y=768"},
+	{"{;hello:5}", ";hello=Hello"},
+	{"{;list}", ";list=red,green,blue"},
+	{"{;list*}", ";list=red;list=green;list=blue"},
+	{"{;keys}", ";keys=semi,%3B,dot,.,comma,%2C"},
+	{"{;keys*}", ";semi=%3B;dot=.;comma=%2C"},
+	// 3.2.8. Form-Style Query Expansion
+	{"{?who}", "?who=fred"},
+	{"{?half}", "?half=50%25"},
+	{"{?x,y}", "?x=1024&y=768"},
+	{"{?x,y,empty}", "?x=1024&y=768&empty="},
+	{"{?x,y,undef}", "?x=1024&y=768"},
+	{"{?var:3}", "?var=val"},
+	{"{?list}", "?list=red,green,blue"},
+	{"{?list*}", "?list=red&list=green&list=blue"},
+	{"{?keys}", "?keys=semi,%3B,dot,.,comma,%2C"},
+	{"{?keys*}", "?semi=%3B&dot=.&comma=%2C"},
+	// 3.2.9. Form-Style Query Continuation
+	{"{&who}", "&who=fred"},
+	{"{&half}", "&half=50%25"},
+	{"?fixed=yes{&x}", "?fixed=yes&x=1024"},
+	{"{&x,y,empty}", "&x=1024&y=768&empty="},
+	{"{&var:3}", "&var=val"},
+	{"{&list}", "&list=red,green,blue"},
+	{"{&list*}", "&list=red&list=green&list=blue"},
+	{"{&keys}", "&keys=semi,%3B,dot,.,comma,%2C"},
+	{"{&keys*}", "&semi=%3B&dot=.&comma=%2C"},
+}
+
+func TestExpandingRfc6570Examples(t *testing.T) {
+	for _, example := range rfc6570Examples {
+		s, err := NewUrlTemplate(example[0]).Expand(rfc6570Variables)
+		if err != nil {
+			t.Errorf("Unexpected error for '%v': %v", example[0], err)
+		} else if s != example[1] {
+			t.Errorf("Expected '%v' to expand to '%v', but got '%v'", example[0], example[1], s)
+		}
+	}
+}
+
+func TestExpandingPrefixOfCompositeValue(t *testing.T) {
+	if _, err := NewUrlTemplate("{list:3}").Expand(rfc6570Variables); err == nil || !strings.Contains(err.Error(), "'list'") {
+		t.Errorf("Expected an error for the prefix of a list, but got: %v", err)
+	}
+}
+
+func TestRenderingKeepsBatchReferences(t *testing.T) {
+	tpl := NewUrlTemplate("{12}/relationships{?types*}")
+	s, err := tpl.Expand(map[string]interface{}{"types": []string{"A", "B"}})
+	expected := "{12}/relationships?types=A&types=B"
+	if err != nil || s != expected {
+		t.Fatalf("Expected to render '%v', but got '%v' (%v)", expected, s, err)
+	}
+}